@@ -0,0 +1,88 @@
+// Command sqsredrive inspects and redrives messages on an SQS dead letter
+// queue.
+//
+//	sqsredrive -dlq jobs-dlq -peek 10
+//	sqsredrive -dlq jobs-dlq -source jobs
+//	sqsredrive -development -endpoint http://localhost:4576/queue -dlq jobs-dlq -source jobs
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/vidsy/awswrappers/sqs"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "", "Local SQS queue endpoint used in development, queue names are appended to it")
+	queueOwner := flag.String("queue-owner", "", "AWS account ID owning the queues when they belong to another account")
+	deadLetterQueueName := flag.String("dlq", "", "Name of the dead letter queue")
+	sourceQueueName := flag.String("source", "", "Name of the queue to redrive messages to")
+	peek := flag.Int("peek", 0, "Print up to this many messages from the dead letter queue without moving them")
+	messageIDs := flag.String("message-ids", "", "Comma separated message IDs to redrive, all messages are redriven when empty")
+	development := flag.Bool("development", false, "Use the endpoint as a local SQS stand-in")
+	flag.Parse()
+
+	if *deadLetterQueueName == "" || (*development && *endpoint == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	config := &sqs.ClientConfig{
+		QueueEndpoint:          *endpoint,
+		MaxNumberOfMessages:    10,
+		VisibilityTimeout:      30,
+		WaitTimeSeconds:        1,
+		QueueOwnerAWSAccountID: *queueOwner,
+	}
+	client := sqs.NewClient(config, *development, nil)
+
+	if *peek > 0 {
+		messages, err := client.PeekMessages(*deadLetterQueueName, *peek)
+		if err != nil {
+			log.Fatalf("Unable to peek messages on '%s': %s", *deadLetterQueueName, err)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(messages)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	if *sourceQueueName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	report, err := client.RedriveMessages(*deadLetterQueueName, *sourceQueueName, messageIDFilter(*messageIDs))
+	if err != nil {
+		log.Fatalf("Unable to redrive messages after moving %d: %s", report.Moved, err)
+	}
+
+	fmt.Printf("Moved %d messages, skipped %d\n", report.Moved, report.Skipped)
+}
+
+func messageIDFilter(messageIDs string) sqs.MessageFilter {
+	if messageIDs == "" {
+		return nil
+	}
+
+	selected := make(map[string]bool)
+	for _, messageID := range strings.Split(messageIDs, ",") {
+		selected[strings.TrimSpace(messageID)] = true
+	}
+
+	return func(message *sqsLib.Message) bool {
+		return message.MessageId != nil && selected[*message.MessageId]
+	}
+}
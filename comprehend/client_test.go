@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	comprehendLib "github.com/aws/aws-sdk-go/service/comprehend"
 	"github.com/aws/aws-sdk-go/service/comprehend/comprehendiface"
 	"github.com/vidsy/awswrappers/comprehend"
@@ -16,7 +17,8 @@ type (
 	MockComprehendClient struct {
 		comprehendiface.ComprehendAPI
 
-		mockDetectKeyPhrases func(input *comprehendLib.DetectKeyPhrasesInput) (*comprehendLib.DetectKeyPhrasesOutput, error)
+		mockDetectKeyPhrases        func(input *comprehendLib.DetectKeyPhrasesInput) (*comprehendLib.DetectKeyPhrasesOutput, error)
+		mockListTopicsDetectionJobs func(input *comprehendLib.ListTopicsDetectionJobsInput) (*comprehendLib.ListTopicsDetectionJobsOutput, error)
 	}
 )
 
@@ -28,6 +30,18 @@ func (m MockComprehendClient) DetectKeyPhrases(input *comprehendLib.DetectKeyPhr
 	return nil, nil
 }
 
+func (m MockComprehendClient) ListTopicsDetectionJobsWithContext(ctx aws.Context, input *comprehendLib.ListTopicsDetectionJobsInput, opts ...request.Option) (*comprehendLib.ListTopicsDetectionJobsOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.mockListTopicsDetectionJobs != nil {
+		return m.mockListTopicsDetectionJobs(input)
+	}
+
+	return &comprehendLib.ListTopicsDetectionJobsOutput{}, nil
+}
+
 func NewTestClient(mockClient *MockComprehendClient) *comprehend.Client {
 	if mockClient == nil {
 		mockClient = &MockComprehendClient{}
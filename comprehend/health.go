@@ -0,0 +1,22 @@
+package comprehend
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	comprehendLib "github.com/aws/aws-sdk-go/service/comprehend"
+	"github.com/pkg/errors"
+)
+
+// HealthCheck checks Comprehend can be reached by listing a topics
+// detection job.
+func (c Client) HealthCheck(ctx context.Context) error {
+	_, err := c.ComprehendAPI.ListTopicsDetectionJobsWithContext(ctx, &comprehendLib.ListTopicsDetectionJobsInput{
+		MaxResults: aws.Int64(1),
+	})
+	if err != nil {
+		return errors.Wrap(err, "Unable to list Comprehend topics detection jobs")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package comprehend_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	comprehendLib "github.com/aws/aws-sdk-go/service/comprehend"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/comprehend"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run(".HealthCheck()", func(t *testing.T) {
+		var cases = []struct {
+			name          string
+			err           error
+			expectedError string
+		}{
+			{"PassesWhenReachable", nil, ""},
+			{"ReturnsErrorWhenUnreachable", errors.New("Unavailable"), "Unable to list Comprehend topics detection jobs: Unavailable"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				called := false
+
+				mockClient := &MockComprehendClient{
+					mockListTopicsDetectionJobs: func(input *comprehendLib.ListTopicsDetectionJobsInput) (*comprehendLib.ListTopicsDetectionJobsOutput, error) {
+						called = true
+						return &comprehendLib.ListTopicsDetectionJobsOutput{}, c.err
+					},
+				}
+
+				client := comprehend.NewClientWithOptions(comprehend.WithAPI(mockClient))
+				err := client.HealthCheck(context.Background())
+
+				assert.True(t, called)
+				if c.expectedError == "" {
+					assert.NoError(t, err)
+				} else {
+					assert.EqualError(t, err, c.expectedError)
+				}
+			})
+		}
+	})
+}
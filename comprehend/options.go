@@ -0,0 +1,97 @@
+package comprehend
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	comprehendLib "github.com/aws/aws-sdk-go/service/comprehend"
+	"github.com/aws/aws-sdk-go/service/comprehend/comprehendiface"
+	"github.com/vidsy/awswrappers"
+)
+
+type (
+	// Option configures a Client created by NewClientWithOptions.
+	Option func(*options)
+
+	options struct {
+		api      comprehendiface.ComprehendAPI
+		endpoint string
+		logger   awswrappers.Logger
+		metrics  awswrappers.Metrics
+		session  *session.Session
+	}
+)
+
+// WithAPI uses the given Comprehend API instead of creating one, the session and
+// endpoint options are ignored.
+func WithAPI(api comprehendiface.ComprehendAPI) Option {
+	return func(o *options) {
+		o.api = api
+	}
+}
+
+// WithEndpoint sends requests to the given endpoint.
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithLogger logs the requests made by the Client and its retries to the
+// given logger, it has no effect when the API is given with WithAPI.
+func WithLogger(logger awswrappers.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithMetrics records the requests made by the Client to the given
+// metrics, it has no effect when the API is given with WithAPI.
+func WithMetrics(metrics awswrappers.Metrics) Option {
+	return func(o *options) {
+		o.metrics = metrics
+	}
+}
+
+// WithSession creates the Comprehend API from the given session, see
+// awswrappers.NewSession.
+func WithSession(sess *session.Session) Option {
+	return func(o *options) {
+		o.session = sess
+	}
+}
+
+// NewClientWithOptions creates a new wrapper from the given options, by
+// default a new default session is used.
+func NewClientWithOptions(opts ...Option) *Client {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.api == nil {
+		if o.session == nil {
+			o.session = session.New()
+		}
+
+		var comprehendClient *comprehendLib.Comprehend
+
+		if o.endpoint != "" {
+			comprehendClient = comprehendLib.New(o.session, awswrappers.DevelopmentConfig(o.endpoint))
+		} else {
+			comprehendClient = comprehendLib.New(o.session)
+		}
+
+		if o.logger != nil {
+			awswrappers.LogRequests(&comprehendClient.Handlers, o.logger)
+		}
+
+		if o.metrics != nil {
+			awswrappers.RecordRequests(&comprehendClient.Handlers, o.metrics)
+		}
+
+		o.api = comprehendClient
+	}
+
+	return &Client{
+		o.api,
+	}
+}
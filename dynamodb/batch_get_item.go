@@ -1,8 +1,344 @@
 package dynamodb
 
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	dynamoDBLib "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/pkg/errors"
+	"github.com/vidsy/awswrappers"
+	"github.com/vidsy/backoff"
+)
+
 type (
 	// BatchGetItem represents an item to be used within a BatchGetItem dynamo
 	// request, it contains the hash key and the value of the thing we are
 	// attempting to retrieve.
 	BatchGetItem map[string][]interface{}
+
+	// Key is the primary key of an item, mapping the partition key and the
+	// optional sort key to their string, number or []byte values.
+	Key map[string]interface{}
+
+	// BatchGetTable describes the items to fetch from a table with BatchGet,
+	// the items are unmarshalled into BindModel.
+	BatchGetTable struct {
+		BindModel                interface{}
+		ConsistentRead           bool
+		ExpressionAttributeNames map[string]string
+		Keys                     []Key
+		ProjectionExpression     string
+	}
+
+	// UnprocessedKeysError is returned when DynamoDB still hasn't processed
+	// some keys after retrying, Keys holds them by table name. The items
+	// that were fetched are still bound to the model.
+	UnprocessedKeysError struct {
+		Keys map[string][]map[string]*dynamoDBLib.AttributeValue
+	}
+
+	batchGetKey struct {
+		tableName string
+		key       map[string]*dynamoDBLib.AttributeValue
+	}
 )
+
+// BatchGet fetches the items with the given keys from each table, keyed by
+// table name, in one set of BatchGetItem requests. Like BatchGetItem the
+// keys are fetched in parallel chunks of 100 and unprocessed keys are
+// retried, keys that are still unprocessed are returned in an
+// UnprocessedKeysError.
+func (c Client) BatchGet(tables map[string]BatchGetTable) error {
+	return c.BatchGetWithContext(context.Background(), tables)
+}
+
+// BatchGetWithContext is BatchGet with a context that can cancel the
+// requests.
+func (c Client) BatchGetWithContext(ctx context.Context, tables map[string]BatchGetTable) error {
+	ctx, span := c.tracer.StartSpan(ctx, "dynamodb.BatchGet")
+	span.SetAttribute("tables", len(tables))
+
+	err := c.batchGetTables(ctx, tables)
+	awswrappers.EndSpan(span, err)
+
+	return err
+}
+
+func (c Client) batchGetTables(ctx context.Context, tables map[string]BatchGetTable) error {
+	requestItems := make(map[string]*dynamoDBLib.KeysAndAttributes, len(tables))
+
+	for tableName, table := range tables {
+		keysAndAttributes := &dynamoDBLib.KeysAndAttributes{
+			Keys: make([]map[string]*dynamoDBLib.AttributeValue, 0, len(table.Keys)),
+		}
+
+		if table.ConsistentRead {
+			keysAndAttributes.ConsistentRead = aws.Bool(true)
+		}
+
+		if len(table.ExpressionAttributeNames) > 0 {
+			keysAndAttributes.ExpressionAttributeNames = aws.StringMap(table.ExpressionAttributeNames)
+		}
+
+		if table.ProjectionExpression != "" {
+			keysAndAttributes.ProjectionExpression = aws.String(table.ProjectionExpression)
+		}
+
+		for _, key := range table.Keys {
+			attributeValues, err := dynamodbattribute.MarshalMap(map[string]interface{}(key))
+			if err != nil {
+				return errors.Wrapf(
+					err,
+					"Problem marshaling key for table '%s' to AttributeValue.",
+					tableName,
+				)
+			}
+
+			keysAndAttributes.Keys = append(keysAndAttributes.Keys, attributeValues)
+		}
+
+		requestItems[tableName] = keysAndAttributes
+	}
+
+	responses, unprocessedKeys, err := c.batchGet(ctx, requestItems)
+	if err != nil {
+		return err
+	}
+
+	for tableName, table := range tables {
+		bindModel := table.BindModel
+
+		err = dynamodbattribute.UnmarshalListOfMaps(responses[tableName], &bindModel)
+		if err != nil {
+			return errors.Wrapf(
+				err,
+				"Problem unmarshaling items of table '%s'",
+				tableName,
+			)
+		}
+	}
+
+	if len(unprocessedKeys) > 0 {
+		return &UnprocessedKeysError{Keys: unprocessedKeys}
+	}
+
+	return nil
+}
+
+// batchGet fetches the keys of the request items in parallel chunks of 100,
+// the items and the keys still unprocessed after retrying are returned by
+// table. DynamoDB doesn't return items in the order of the keys, so the
+// items of a table are in no particular order.
+func (c Client) batchGet(ctx context.Context, requestItems map[string]*dynamoDBLib.KeysAndAttributes) (map[string][]map[string]*dynamoDBLib.AttributeValue, map[string][]map[string]*dynamoDBLib.AttributeValue, error) {
+	var keys []batchGetKey
+	for _, tableName := range sortedTableNames(requestItems) {
+		for _, key := range requestItems[tableName].Keys {
+			keys = append(keys, batchGetKey{tableName, key})
+		}
+	}
+
+	chunkResponses := make([]map[string][]map[string]*dynamoDBLib.AttributeValue, (len(keys)+batchGetMaxItems-1)/batchGetMaxItems)
+	unprocessedKeys := make(map[string][]map[string]*dynamoDBLib.AttributeValue)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var firstErr error
+	var resultsMutex sync.Mutex
+	var chunksWaitGroup sync.WaitGroup
+	semaphore := make(chan struct{}, c.batchGetConcurrency)
+
+	for i := 0; i < len(keys); i += batchGetMaxItems {
+		end := i + batchGetMaxItems
+
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		chunksWaitGroup.Add(1)
+		semaphore <- struct{}{}
+
+		go func(start int, end int) {
+			defer chunksWaitGroup.Done()
+			defer func() { <-semaphore }()
+
+			chunkRequestItems := batchGetChunkRequestItems(requestItems, keys[start:end])
+			responses, unprocessed, err := c.batchGetChunk(ctx, chunkRequestItems)
+
+			resultsMutex.Lock()
+			defer resultsMutex.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = errors.Wrapf(
+						err,
+						"Error fetching BatchGetItem table '%s', range %d:%d",
+						strings.Join(sortedTableNames(chunkRequestItems), ", "),
+						start,
+						end,
+					)
+					cancel()
+				}
+
+				return
+			}
+
+			chunkResponses[start/batchGetMaxItems] = responses
+			for tableName, keysAndAttributes := range unprocessed {
+				unprocessedKeys[tableName] = append(unprocessedKeys[tableName], keysAndAttributes.Keys...)
+			}
+		}(i, end)
+	}
+
+	chunksWaitGroup.Wait()
+
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+
+	responses := make(map[string][]map[string]*dynamoDBLib.AttributeValue, len(requestItems))
+	for _, chunk := range chunkResponses {
+		for tableName, items := range chunk {
+			responses[tableName] = append(responses[tableName], items...)
+		}
+	}
+
+	for tableName, keys := range unprocessedKeys {
+		c.logger.Log("DynamoDB keys unprocessed after retrying", awswrappers.Fields{
+			"table":       tableName,
+			"unprocessed": len(keys),
+		})
+	}
+
+	return responses, unprocessedKeys, nil
+}
+
+// batchGetChunk fetches the request items of a single chunk, retrying the
+// keys DynamoDB leaves unprocessed with backoff. The keys still unprocessed
+// once the retries run out are returned.
+func (c Client) batchGetChunk(ctx context.Context, requestItems map[string]*dynamoDBLib.KeysAndAttributes) (map[string][]map[string]*dynamoDBLib.AttributeValue, map[string]*dynamoDBLib.KeysAndAttributes, error) {
+	responses := make(map[string][]map[string]*dynamoDBLib.AttributeValue)
+	var requestErr error
+	pending := requestItems
+
+	bp := backoff.Policy{
+		Intervals: jitteredIntervals(batchGetRetryIntervals),
+	}
+
+	bp.Perform(func() (bool, error) {
+		batchGetItemInput := &dynamoDBLib.BatchGetItemInput{
+			RequestItems: pending,
+		}
+
+		output, err := c.DynamoDBAPI.BatchGetItemWithContext(ctx, batchGetItemInput)
+		if err != nil {
+			requestErr = err
+			return true, nil
+		}
+
+		for tableName, items := range output.Responses {
+			responses[tableName] = append(responses[tableName], items...)
+		}
+
+		pending = make(map[string]*dynamoDBLib.KeysAndAttributes)
+		for tableName, keysAndAttributes := range output.UnprocessedKeys {
+			if keysAndAttributes != nil && len(keysAndAttributes.Keys) > 0 {
+				pending[tableName] = keysAndAttributes
+			}
+		}
+
+		return len(pending) == 0, nil
+	})
+
+	if requestErr != nil {
+		return nil, nil, requestErr
+	}
+
+	return responses, pending, nil
+}
+
+// batchGetChunkRequestItems builds the request items for a chunk of keys,
+// keeping the projection and consistency of each table.
+func batchGetChunkRequestItems(requestItems map[string]*dynamoDBLib.KeysAndAttributes, keys []batchGetKey) map[string]*dynamoDBLib.KeysAndAttributes {
+	chunkRequestItems := make(map[string]*dynamoDBLib.KeysAndAttributes)
+
+	for _, key := range keys {
+		keysAndAttributes, ok := chunkRequestItems[key.tableName]
+		if !ok {
+			table := requestItems[key.tableName]
+			keysAndAttributes = &dynamoDBLib.KeysAndAttributes{
+				AttributesToGet:          table.AttributesToGet,
+				ConsistentRead:           table.ConsistentRead,
+				ExpressionAttributeNames: table.ExpressionAttributeNames,
+				ProjectionExpression:     table.ProjectionExpression,
+			}
+			chunkRequestItems[key.tableName] = keysAndAttributes
+		}
+
+		keysAndAttributes.Keys = append(keysAndAttributes.Keys, key.key)
+	}
+
+	return chunkRequestItems
+}
+
+func sortedTableNames(requestItems map[string]*dynamoDBLib.KeysAndAttributes) []string {
+	tableNames := make([]string, 0, len(requestItems))
+	for tableName := range requestItems {
+		tableNames = append(tableNames, tableName)
+	}
+
+	sort.Strings(tableNames)
+
+	return tableNames
+}
+
+func (e UnprocessedKeysError) Error() string {
+	tableNames := make([]string, 0, len(e.Keys))
+	for tableName := range e.Keys {
+		tableNames = append(tableNames, tableName)
+	}
+
+	sort.Strings(tableNames)
+
+	tables := make([]string, 0, len(tableNames))
+	for _, tableName := range tableNames {
+		keys := make([]string, 0, len(e.Keys[tableName]))
+		for _, key := range e.Keys[tableName] {
+			keys = append(keys, formatKey(key))
+		}
+
+		tables = append(tables, fmt.Sprintf("table '%s' keys [%s]", tableName, strings.Join(keys, ", ")))
+	}
+
+	return fmt.Sprintf("Unable to fetch unprocessed keys after retrying, %s", strings.Join(tables, "; "))
+}
+
+func formatKey(key map[string]*dynamoDBLib.AttributeValue) string {
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	attributes := make([]string, 0, len(names))
+	for _, name := range names {
+		value := key[name]
+
+		switch {
+		case value.S != nil:
+			attributes = append(attributes, fmt.Sprintf("%s=%s", name, aws.StringValue(value.S)))
+		case value.N != nil:
+			attributes = append(attributes, fmt.Sprintf("%s=%s", name, aws.StringValue(value.N)))
+		default:
+			attributes = append(attributes, fmt.Sprintf("%s=%s", name, value.B))
+		}
+	}
+
+	return strings.Join(attributes, " ")
+}
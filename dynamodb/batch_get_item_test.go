@@ -0,0 +1,262 @@
+package dynamodb_test
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	dynamoDBLib "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/dynamodb"
+)
+
+type (
+	TestCommentModel struct {
+		PostID    string `dynamodbav:"post_id"`
+		CreatedAt int    `dynamodbav:"created_at"`
+	}
+)
+
+func TestBatchGetItem(t *testing.T) {
+	t.Run(".BatchGetItem()", func(t *testing.T) {
+		tableName := "test_table_name"
+
+		data := dynamodb.BatchGetItem{
+			"id": []interface{}{
+				"some_value",
+				"some_other_value",
+			},
+		}
+
+		t.Run("RetriesUnprocessedKeys", func(t *testing.T) {
+			callCount := 0
+			mockSDKClient := &MockSDKClient{
+				mockBatchGetItem: func(input *dynamoDBLib.BatchGetItemInput) (*dynamoDBLib.BatchGetItemOutput, error) {
+					callCount++
+					keys := input.RequestItems[tableName].Keys
+
+					output := &dynamoDBLib.BatchGetItemOutput{
+						Responses: map[string][]map[string]*dynamoDBLib.AttributeValue{
+							tableName: keys[:1],
+						},
+					}
+
+					if len(keys) > 1 {
+						output.UnprocessedKeys = map[string]*dynamoDBLib.KeysAndAttributes{
+							tableName: {Keys: keys[1:]},
+						}
+					}
+
+					return output, nil
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			var bindModel []TestBatchGetModel
+
+			err = testClient.BatchGetItem(tableName, generateBatchGetItemData(3), &bindModel)
+			assert.NoError(t, err)
+
+			assert.Len(t, bindModel, 3)
+			assert.Equal(t, 3, callCount)
+		})
+
+		t.Run("ReturnsKeysStillUnprocessed", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{
+				mockBatchGetItem: func(input *dynamoDBLib.BatchGetItemInput) (*dynamoDBLib.BatchGetItemOutput, error) {
+					keys := input.RequestItems[tableName].Keys
+					if len(keys) > 1 {
+						return &dynamoDBLib.BatchGetItemOutput{
+							Responses: map[string][]map[string]*dynamoDBLib.AttributeValue{
+								tableName: keys[1:],
+							},
+							UnprocessedKeys: map[string]*dynamoDBLib.KeysAndAttributes{
+								tableName: {Keys: keys[:1]},
+							},
+						}, nil
+					}
+
+					return &dynamoDBLib.BatchGetItemOutput{
+						UnprocessedKeys: map[string]*dynamoDBLib.KeysAndAttributes{
+							tableName: {Keys: keys},
+						},
+					}, nil
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			var bindModel []TestBatchGetModel
+
+			err = testClient.BatchGetItem(tableName, data, &bindModel)
+
+			if assert.IsType(t, &dynamodb.UnprocessedKeysError{}, err) {
+				unprocessedKeysError := err.(*dynamodb.UnprocessedKeysError)
+				assert.Len(t, unprocessedKeysError.Keys[tableName], 1)
+				assert.Equal(
+					t,
+					"Unable to fetch unprocessed keys after retrying, table 'test_table_name' keys [id=some_value]",
+					err.Error(),
+				)
+			}
+			assert.Len(t, bindModel, 1)
+		})
+
+		t.Run("LimitsConcurrentChunks", func(t *testing.T) {
+			var mutex sync.Mutex
+			inFlight := 0
+			maxInFlight := 0
+
+			mockSDKClient := &MockSDKClient{
+				mockBatchGetItem: func(input *dynamoDBLib.BatchGetItemInput) (*dynamoDBLib.BatchGetItemOutput, error) {
+					mutex.Lock()
+					inFlight++
+					if inFlight > maxInFlight {
+						maxInFlight = inFlight
+					}
+					mutex.Unlock()
+
+					time.Sleep(10 * time.Millisecond)
+
+					mutex.Lock()
+					inFlight--
+					mutex.Unlock()
+
+					return &dynamoDBLib.BatchGetItemOutput{}, nil
+				},
+			}
+
+			testClient, err := dynamodb.NewClientWithOptions(
+				dynamodb.WithAPI(mockSDKClient),
+				dynamodb.WithBatchGetConcurrency(2),
+			)
+			assert.Nil(t, err)
+
+			var bindModel []TestBatchGetModel
+
+			err = testClient.BatchGetItem(tableName, generateBatchGetItemData(500), &bindModel)
+			assert.NoError(t, err)
+
+			assert.Equal(t, 2, maxInFlight)
+		})
+
+		t.Run("MarshalsNumberKeys", func(t *testing.T) {
+			var keys []map[string]*dynamoDBLib.AttributeValue
+
+			mockSDKClient := &MockSDKClient{
+				mockBatchGetItem: func(input *dynamoDBLib.BatchGetItemInput) (*dynamoDBLib.BatchGetItemOutput, error) {
+					keys = input.RequestItems[tableName].Keys
+					return &dynamoDBLib.BatchGetItemOutput{}, nil
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			var bindModel []TestBatchGetModel
+
+			err = testClient.BatchGetItem(tableName, dynamodb.BatchGetItem{"id": {5, 2.5}}, &bindModel)
+			assert.NoError(t, err)
+
+			if assert.Len(t, keys, 2) {
+				assert.Equal(t, "5", *keys[0]["id"].N)
+				assert.Equal(t, "2.5", *keys[1]["id"].N)
+			}
+		})
+	})
+}
+
+func TestBatchGet(t *testing.T) {
+	t.Run(".BatchGet()", func(t *testing.T) {
+		t.Run("FetchesCompositeKeysFromEachTable", func(t *testing.T) {
+			var requestItems map[string]*dynamoDBLib.KeysAndAttributes
+
+			mockSDKClient := &MockSDKClient{
+				mockBatchGetItem: func(input *dynamoDBLib.BatchGetItemInput) (*dynamoDBLib.BatchGetItemOutput, error) {
+					requestItems = input.RequestItems
+
+					return &dynamoDBLib.BatchGetItemOutput{
+						Responses: map[string][]map[string]*dynamoDBLib.AttributeValue{
+							"comments": input.RequestItems["comments"].Keys,
+							"users":    input.RequestItems["users"].Keys,
+						},
+					}, nil
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			var comments []TestCommentModel
+			var users []TestBatchGetModel
+
+			err = testClient.BatchGet(map[string]dynamodb.BatchGetTable{
+				"comments": {
+					BindModel:            &comments,
+					ConsistentRead:       true,
+					Keys:                 []dynamodb.Key{{"post_id": "foo", "created_at": 10}, {"post_id": "foo", "created_at": 20}},
+					ProjectionExpression: "post_id, created_at",
+				},
+				"users": {
+					BindModel: &users,
+					Keys:      []dynamodb.Key{{"id": "bar"}},
+				},
+			})
+			assert.NoError(t, err)
+
+			assert.Equal(t, []TestCommentModel{{"foo", 10}, {"foo", 20}}, comments)
+			assert.Equal(t, []TestBatchGetModel{{"bar"}}, users)
+
+			assert.True(t, *requestItems["comments"].ConsistentRead)
+			assert.Equal(t, "post_id, created_at", *requestItems["comments"].ProjectionExpression)
+			assert.Nil(t, requestItems["users"].ConsistentRead)
+			assert.Nil(t, requestItems["users"].ProjectionExpression)
+		})
+
+		t.Run("ChunksKeysAcrossTables", func(t *testing.T) {
+			var mutex sync.Mutex
+			var chunkSizes []int
+
+			mockSDKClient := &MockSDKClient{
+				mockBatchGetItem: func(input *dynamoDBLib.BatchGetItemInput) (*dynamoDBLib.BatchGetItemOutput, error) {
+					size := 0
+					for _, keysAndAttributes := range input.RequestItems {
+						size += len(keysAndAttributes.Keys)
+					}
+
+					mutex.Lock()
+					chunkSizes = append(chunkSizes, size)
+					mutex.Unlock()
+
+					return &dynamoDBLib.BatchGetItemOutput{}, nil
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			tables := map[string]dynamodb.BatchGetTable{}
+			for _, tableName := range []string{"foo", "bar"} {
+				var keys []dynamodb.Key
+				for i := 0; i < 60; i++ {
+					keys = append(keys, dynamodb.Key{"id": i})
+				}
+
+				tables[tableName] = dynamodb.BatchGetTable{
+					BindModel: &[]TestBatchGetModel{},
+					Keys:      keys,
+				}
+			}
+
+			err = testClient.BatchGet(tables)
+			assert.NoError(t, err)
+
+			sort.Ints(chunkSizes)
+			assert.Equal(t, []int{20, 100}, chunkSizes)
+		})
+	})
+}
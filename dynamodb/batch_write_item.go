@@ -0,0 +1,399 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/base64"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	dynamoDBLib "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/pkg/errors"
+	"github.com/vidsy/awswrappers"
+	"github.com/vidsy/backoff"
+)
+
+type (
+	// BatchWriteFailure represents an item of a BatchWrite that couldn't be
+	// written, Index is the position of the item in the puts or deletes
+	// given to BatchWrite.
+	BatchWriteFailure struct {
+		Index     int
+		TableName string
+		Message   string
+	}
+
+	// BatchWriteReport lists the items of a BatchWrite that still failed
+	// after retrying, Written is the number of items that were written.
+	BatchWriteReport struct {
+		Written       int
+		FailedPuts    []BatchWriteFailure
+		FailedDeletes []BatchWriteFailure
+	}
+
+	batchWriteEntry struct {
+		delete    bool
+		index     int
+		tableName string
+		request   *dynamoDBLib.WriteRequest
+	}
+
+	// batchWriteKeySchemas caches the key attribute names of the tables of
+	// a BatchWrite, used to match unprocessed puts to their entries.
+	batchWriteKeySchemas struct {
+		mutex sync.Mutex
+		names map[string][]string
+	}
+
+	batchWriteFailedEntry struct {
+		batchWriteEntry
+		message string
+	}
+)
+
+// BatchWrite puts and deletes the given items with BatchWriteItem, the items
+// are grouped by table into chunks of 25 that are written in parallel and
+// the items DynamoDB leaves unprocessed are retried with backoff. An error
+// is returned when an item can't be marshaled or a request fails with an
+// error retrying can't fix, such as a missing table or an invalid item,
+// other items that can't be written are listed in the report.
+func (c Client) BatchWrite(puts []Marshaler, deletes []Deletable) (*BatchWriteReport, error) {
+	return c.BatchWriteWithContext(context.Background(), puts, deletes)
+}
+
+// BatchWriteWithContext is BatchWrite with a context, once the context is
+// cancelled failed items are no longer retried.
+func (c Client) BatchWriteWithContext(ctx context.Context, puts []Marshaler, deletes []Deletable) (*BatchWriteReport, error) {
+	ctx, span := c.tracer.StartSpan(ctx, "dynamodb.BatchWrite")
+	span.SetAttribute("puts", len(puts))
+	span.SetAttribute("deletes", len(deletes))
+
+	report, err := c.batchWrite(ctx, puts, deletes)
+	awswrappers.EndSpan(span, err)
+
+	return report, err
+}
+
+func (c Client) batchWrite(ctx context.Context, puts []Marshaler, deletes []Deletable) (*BatchWriteReport, error) {
+	entriesByTable := make(map[string][]batchWriteEntry)
+
+	for i, item := range puts {
+		putItemInput, err := item.Marshal()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Problem marshaling put item %d", i)
+		}
+
+		if putItemInput == nil || putItemInput.TableName == nil {
+			return nil, errors.Errorf("Problem marshaling put item %d, no table name returned", i)
+		}
+
+		tableName := *putItemInput.TableName
+		entriesByTable[tableName] = append(entriesByTable[tableName], batchWriteEntry{
+			index:     i,
+			tableName: tableName,
+			request: &dynamoDBLib.WriteRequest{
+				PutRequest: &dynamoDBLib.PutRequest{
+					Item: putItemInput.Item,
+				},
+			},
+		})
+	}
+
+	for i, item := range deletes {
+		key, err := dynamodbattribute.MarshalMap(item.Key())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Problem marshaling key of delete item %d to AttributeValue.", i)
+		}
+
+		tableName := item.TableName()
+		entriesByTable[tableName] = append(entriesByTable[tableName], batchWriteEntry{
+			delete:    true,
+			index:     i,
+			tableName: tableName,
+			request: &dynamoDBLib.WriteRequest{
+				DeleteRequest: &dynamoDBLib.DeleteRequest{
+					Key: key,
+				},
+			},
+		})
+	}
+
+	report := &BatchWriteReport{}
+	keySchemas := &batchWriteKeySchemas{
+		names: make(map[string][]string),
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var firstErr error
+	var reportMutex sync.Mutex
+	var chunksWaitGroup sync.WaitGroup
+	semaphore := make(chan struct{}, c.batchWriteConcurrency)
+
+	for _, entries := range entriesByTable {
+		for i := 0; i < len(entries); i += batchWriteMaxItems {
+			end := i + batchWriteMaxItems
+
+			if end > len(entries) {
+				end = len(entries)
+			}
+
+			chunksWaitGroup.Add(1)
+			semaphore <- struct{}{}
+
+			go func(chunk []batchWriteEntry) {
+				defer chunksWaitGroup.Done()
+				defer func() { <-semaphore }()
+
+				failed, err := c.batchWriteChunk(ctx, keySchemas, chunk)
+
+				reportMutex.Lock()
+				defer reportMutex.Unlock()
+
+				if err != nil {
+					if firstErr == nil {
+						firstErr = errors.Wrapf(
+							err,
+							"Error writing BatchWriteItem table '%s'",
+							chunk[0].tableName,
+						)
+						cancel()
+					}
+
+					return
+				}
+
+				report.Written += len(chunk) - len(failed)
+
+				for _, entry := range failed {
+					failure := BatchWriteFailure{
+						Index:     entry.index,
+						TableName: entry.tableName,
+						Message:   entry.message,
+					}
+
+					if entry.delete {
+						report.FailedDeletes = append(report.FailedDeletes, failure)
+					} else {
+						report.FailedPuts = append(report.FailedPuts, failure)
+					}
+				}
+			}(entries[i:end])
+		}
+	}
+
+	chunksWaitGroup.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.Slice(report.FailedPuts, func(i, j int) bool {
+		return report.FailedPuts[i].Index < report.FailedPuts[j].Index
+	})
+	sort.Slice(report.FailedDeletes, func(i, j int) bool {
+		return report.FailedDeletes[i].Index < report.FailedDeletes[j].Index
+	})
+
+	if len(report.FailedPuts) > 0 || len(report.FailedDeletes) > 0 {
+		c.logger.Log("DynamoDB batch write items failed", awswrappers.Fields{
+			"failed_deletes": len(report.FailedDeletes),
+			"failed_puts":    len(report.FailedPuts),
+			"written":        report.Written,
+		})
+	}
+
+	return report, nil
+}
+
+// batchWriteChunk writes the entries of a single table, retrying the
+// entries that fail or are left unprocessed with backoff. The entries that
+// still failed once the retries run out are returned with the reason, and
+// an error that retrying can't fix is returned straight away.
+func (c Client) batchWriteChunk(ctx context.Context, keySchemas *batchWriteKeySchemas, entries []batchWriteEntry) ([]batchWriteFailedEntry, error) {
+	tableName := entries[0].tableName
+	pending := entries
+	message := ""
+	var requestErr error
+
+	bp := backoff.Policy{
+		Intervals: jitteredIntervals(batchWriteRetryIntervals),
+	}
+
+	bp.Perform(func() (bool, error) {
+		requests := make([]*dynamoDBLib.WriteRequest, 0, len(pending))
+		for _, entry := range pending {
+			requests = append(requests, entry.request)
+		}
+
+		batchWriteItemInput := &dynamoDBLib.BatchWriteItemInput{
+			RequestItems: map[string][]*dynamoDBLib.WriteRequest{
+				tableName: requests,
+			},
+		}
+
+		output, err := c.DynamoDBAPI.BatchWriteItemWithContext(ctx, batchWriteItemInput)
+		if err != nil {
+			if !awswrappers.IsRetryableError(err) {
+				requestErr = err
+				return true, nil
+			}
+
+			message = err.Error()
+			return ctx.Err() != nil, nil
+		}
+
+		pending = c.unprocessedBatchWriteEntries(ctx, keySchemas, tableName, pending, output.UnprocessedItems[tableName])
+		message = "Item unprocessed after retrying"
+
+		return len(pending) == 0 || ctx.Err() != nil, nil
+	})
+
+	if requestErr != nil {
+		return nil, requestErr
+	}
+
+	failed := make([]batchWriteFailedEntry, 0, len(pending))
+	for _, entry := range pending {
+		failed = append(failed, batchWriteFailedEntry{entry, message})
+	}
+
+	return failed, nil
+}
+
+// unprocessedBatchWriteEntries matches the unprocessed requests returned by
+// DynamoDB to the entries they were sent for by the key attributes of the
+// table. When a request can't be matched every entry stays pending, as
+// resending a put or delete that was already written is harmless.
+func (c Client) unprocessedBatchWriteEntries(ctx context.Context, keySchemas *batchWriteKeySchemas, tableName string, entries []batchWriteEntry, unprocessed []*dynamoDBLib.WriteRequest) []batchWriteEntry {
+	if len(unprocessed) == 0 {
+		return nil
+	}
+
+	var keyNames []string
+	requestKey := func(request *dynamoDBLib.WriteRequest) (string, bool) {
+		switch {
+		case request == nil:
+			return "", false
+		case request.DeleteRequest != nil:
+			return batchWriteRequestKey("delete", request.DeleteRequest.Key, nil)
+		case request.PutRequest != nil:
+			if keyNames == nil {
+				names, err := c.tableKeyNames(ctx, keySchemas, tableName)
+				if err != nil {
+					c.logger.Log("Unable to match unprocessed DynamoDB items to the batch", awswrappers.Fields{
+						"error": err.Error(),
+						"table": tableName,
+					})
+
+					return "", false
+				}
+
+				keyNames = names
+			}
+
+			return batchWriteRequestKey("put", request.PutRequest.Item, keyNames)
+		}
+
+		return "", false
+	}
+
+	entriesByKey := make(map[string][]batchWriteEntry, len(entries))
+	for _, entry := range entries {
+		key, ok := requestKey(entry.request)
+		if !ok {
+			return entries
+		}
+
+		entriesByKey[key] = append(entriesByKey[key], entry)
+	}
+
+	pending := make([]batchWriteEntry, 0, len(unprocessed))
+	for _, request := range unprocessed {
+		key, ok := requestKey(request)
+		if !ok || len(entriesByKey[key]) == 0 {
+			return entries
+		}
+
+		pending = append(pending, entriesByKey[key][0])
+		entriesByKey[key] = entriesByKey[key][1:]
+	}
+
+	return pending
+}
+
+// tableKeyNames returns the names of the key attributes of the table,
+// describing it the first time it's needed by the BatchWrite.
+func (c Client) tableKeyNames(ctx context.Context, keySchemas *batchWriteKeySchemas, tableName string) ([]string, error) {
+	keySchemas.mutex.Lock()
+	defer keySchemas.mutex.Unlock()
+
+	if names, ok := keySchemas.names[tableName]; ok {
+		return names, nil
+	}
+
+	output, err := c.DynamoDBAPI.DescribeTableWithContext(ctx, &dynamoDBLib.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to describe DynamoDB table '%s'", tableName)
+	}
+
+	if output == nil || output.Table == nil || len(output.Table.KeySchema) == 0 {
+		return nil, errors.Errorf("No key schema returned for DynamoDB table '%s'", tableName)
+	}
+
+	names := make([]string, 0, len(output.Table.KeySchema))
+	for _, element := range output.Table.KeySchema {
+		names = append(names, aws.StringValue(element.AttributeName))
+	}
+
+	keySchemas.names[tableName] = names
+
+	return names, nil
+}
+
+// batchWriteRequestKey identifies a request by the given key attributes, or
+// by all the attributes when names is nil. Numbers are compared by value so
+// that "5" and "5.0" are the same key.
+func batchWriteRequestKey(kind string, attributes map[string]*dynamoDBLib.AttributeValue, names []string) (string, bool) {
+	if names == nil {
+		for name := range attributes {
+			names = append(names, name)
+		}
+	}
+
+	sortedNames := append([]string(nil), names...)
+	sort.Strings(sortedNames)
+
+	parts := []string{kind}
+	for _, name := range sortedNames {
+		value, ok := attributes[name]
+		if !ok || value == nil {
+			return "", false
+		}
+
+		switch {
+		case value.S != nil:
+			parts = append(parts, strconv.Quote(name)+"=S"+strconv.Quote(*value.S))
+		case value.N != nil:
+			number := *value.N
+			if rat, ok := new(big.Rat).SetString(number); ok {
+				number = rat.RatString()
+			}
+
+			parts = append(parts, strconv.Quote(name)+"=N"+strconv.Quote(number))
+		case value.B != nil:
+			parts = append(parts, strconv.Quote(name)+"=B"+base64.StdEncoding.EncodeToString(value.B))
+		default:
+			return "", false
+		}
+	}
+
+	return strings.Join(parts, ","), true
+}
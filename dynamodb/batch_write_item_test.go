@@ -0,0 +1,340 @@
+package dynamodb_test
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	dynamoDBLib "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/dynamodb"
+)
+
+type (
+	TestWriteModel struct {
+		ID    string `dynamodbav:"id"`
+		Table string `dynamodbav:"-"`
+	}
+
+	TestNumberWriteModel struct {
+		ID int `dynamodbav:"id"`
+	}
+)
+
+func (m TestNumberWriteModel) Marshal() (*dynamoDBLib.PutItemInput, error) {
+	item, err := dynamodbattribute.MarshalMap(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamoDBLib.PutItemInput{
+		Item:      item,
+		TableName: aws.String("foo"),
+	}, nil
+}
+
+func (m TestWriteModel) Marshal() (*dynamoDBLib.PutItemInput, error) {
+	item, err := dynamodbattribute.MarshalMap(m)
+	if err != nil {
+		return nil, err
+	}
+
+	putItemInput := &dynamoDBLib.PutItemInput{
+		Item: item,
+	}
+
+	if m.Table != "" {
+		putItemInput.TableName = aws.String(m.Table)
+	}
+
+	return putItemInput, nil
+}
+
+func (m TestWriteModel) Key() map[string]interface{} {
+	return map[string]interface{}{"id": m.ID}
+}
+
+func (m TestWriteModel) TableName() string {
+	return m.Table
+}
+
+func TestBatchWrite(t *testing.T) {
+	t.Run(".BatchWrite()", func(t *testing.T) {
+		t.Run("ChunksItemsByTable", func(t *testing.T) {
+			var mutex sync.Mutex
+			var chunkSizes []int
+
+			mockSDKClient := &MockSDKClient{
+				mockBatchWriteItem: func(input *dynamoDBLib.BatchWriteItemInput) (*dynamoDBLib.BatchWriteItemOutput, error) {
+					mutex.Lock()
+					defer mutex.Unlock()
+
+					assert.Len(t, input.RequestItems, 1)
+					for _, requests := range input.RequestItems {
+						chunkSizes = append(chunkSizes, len(requests))
+					}
+
+					return &dynamoDBLib.BatchWriteItemOutput{}, nil
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			report, err := testClient.BatchWrite(generatePuts("foo", 30), generateDeletes("bar", 5))
+
+			sort.Ints(chunkSizes)
+
+			assert.NoError(t, err)
+			assert.Equal(t, []int{5, 5, 25}, chunkSizes)
+			assert.Equal(t, 35, report.Written)
+			assert.Empty(t, report.FailedPuts)
+			assert.Empty(t, report.FailedDeletes)
+		})
+
+		t.Run("RetriesUnprocessedItems", func(t *testing.T) {
+			callCount := 0
+
+			mockSDKClient := &MockSDKClient{
+				mockBatchWriteItem: func(input *dynamoDBLib.BatchWriteItemInput) (*dynamoDBLib.BatchWriteItemOutput, error) {
+					callCount++
+
+					if callCount == 1 {
+						return &dynamoDBLib.BatchWriteItemOutput{
+							UnprocessedItems: map[string][]*dynamoDBLib.WriteRequest{
+								"foo": {copyWriteRequest(input.RequestItems["foo"][1])},
+							},
+						}, nil
+					}
+
+					assert.Len(t, input.RequestItems["foo"], 1)
+
+					return &dynamoDBLib.BatchWriteItemOutput{}, nil
+				},
+				mockDescribeTable: describeTableWithKeys("id"),
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			report, err := testClient.BatchWrite(generatePuts("foo", 3), nil)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 2, callCount)
+			assert.Equal(t, 3, report.Written)
+			assert.Empty(t, report.FailedPuts)
+		})
+
+		t.Run("MatchesUnprocessedItemsByKey", func(t *testing.T) {
+			var sentKeys [][]string
+
+			mockSDKClient := &MockSDKClient{
+				mockBatchWriteItem: func(input *dynamoDBLib.BatchWriteItemInput) (*dynamoDBLib.BatchWriteItemOutput, error) {
+					var keys []string
+					for _, request := range input.RequestItems["foo"] {
+						keys = append(keys, *request.PutRequest.Item["id"].N)
+					}
+
+					sentKeys = append(sentKeys, keys)
+
+					if len(sentKeys) > 1 {
+						return &dynamoDBLib.BatchWriteItemOutput{}, nil
+					}
+
+					return &dynamoDBLib.BatchWriteItemOutput{
+						UnprocessedItems: map[string][]*dynamoDBLib.WriteRequest{
+							"foo": {
+								{
+									PutRequest: &dynamoDBLib.PutRequest{
+										Item: map[string]*dynamoDBLib.AttributeValue{
+											"id":   {N: aws.String("2.0")},
+											"name": {S: aws.String("changed")},
+										},
+									},
+								},
+							},
+						},
+					}, nil
+				},
+				mockDescribeTable: describeTableWithKeys("id"),
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			puts := []dynamodb.Marshaler{TestNumberWriteModel{1}, TestNumberWriteModel{2}, TestNumberWriteModel{3}}
+			report, err := testClient.BatchWrite(puts, nil)
+
+			assert.NoError(t, err)
+			assert.Equal(t, [][]string{{"1", "2", "3"}, {"2"}}, sentKeys)
+			assert.Equal(t, 3, report.Written)
+			assert.Empty(t, report.FailedPuts)
+		})
+
+		t.Run("KeepsEveryItemPendingWhenUnprocessedItemsDontMatch", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{
+				mockBatchWriteItem: func(input *dynamoDBLib.BatchWriteItemInput) (*dynamoDBLib.BatchWriteItemOutput, error) {
+					return &dynamoDBLib.BatchWriteItemOutput{
+						UnprocessedItems: map[string][]*dynamoDBLib.WriteRequest{
+							"foo": {
+								{
+									DeleteRequest: &dynamoDBLib.DeleteRequest{
+										Key: map[string]*dynamoDBLib.AttributeValue{
+											"id": {S: aws.String("unknown")},
+										},
+									},
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			report, err := testClient.BatchWrite(nil, generateDeletes("foo", 2))
+
+			assert.NoError(t, err)
+			assert.Equal(t, 0, report.Written)
+			assert.Len(t, report.FailedDeletes, 2)
+		})
+
+		t.Run("ReportsItemsStillUnprocessed", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{
+				mockBatchWriteItem: func(input *dynamoDBLib.BatchWriteItemInput) (*dynamoDBLib.BatchWriteItemOutput, error) {
+					return &dynamoDBLib.BatchWriteItemOutput{
+						UnprocessedItems: map[string][]*dynamoDBLib.WriteRequest{
+							"bar": {copyWriteRequest(input.RequestItems["bar"][0])},
+						},
+					}, nil
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			report, err := testClient.BatchWrite(nil, generateDeletes("bar", 3))
+
+			assert.NoError(t, err)
+			assert.Equal(t, 2, report.Written)
+			assert.Equal(t, []dynamodb.BatchWriteFailure{
+				{Index: 0, TableName: "bar", Message: "Item unprocessed after retrying"},
+			}, report.FailedDeletes)
+		})
+
+		t.Run("ReturnsErrorForPutWithoutTableName", func(t *testing.T) {
+			testClient, err := NewTestClient(nil)
+			assert.Nil(t, err)
+
+			puts := []dynamodb.Marshaler{TestWriteModel{"foo", "bar"}, TestWriteModel{ID: "baz"}}
+			_, err = testClient.BatchWrite(puts, nil)
+
+			assert.EqualError(t, err, "Problem marshaling put item 1, no table name returned")
+		})
+
+		t.Run("ReturnsNonRetryableErrorWithoutRetrying", func(t *testing.T) {
+			callCount := 0
+
+			mockSDKClient := &MockSDKClient{
+				mockBatchWriteItem: func(input *dynamoDBLib.BatchWriteItemInput) (*dynamoDBLib.BatchWriteItemOutput, error) {
+					callCount++
+					return nil, awserr.New("ValidationException", "Provided list of item keys contains duplicates", nil)
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			report, err := testClient.BatchWrite(generatePuts("foo", 2), nil)
+
+			assert.Nil(t, report)
+			assert.EqualError(t, err, "Error writing BatchWriteItem table 'foo': ValidationException: Provided list of item keys contains duplicates")
+			assert.Equal(t, 1, callCount)
+		})
+
+		t.Run("ReportsItemsFailedByClientError", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{
+				mockBatchWriteItem: func(input *dynamoDBLib.BatchWriteItemInput) (*dynamoDBLib.BatchWriteItemOutput, error) {
+					return nil, errors.New("Client error")
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			report, err := testClient.BatchWrite(generatePuts("foo", 2), nil)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 0, report.Written)
+			if assert.Len(t, report.FailedPuts, 2) {
+				assert.Equal(t, 1, report.FailedPuts[1].Index)
+				assert.Equal(t, "Client error", report.FailedPuts[1].Message)
+			}
+		})
+	})
+}
+
+func describeTableWithKeys(names ...string) func(*dynamoDBLib.DescribeTableInput) (*dynamoDBLib.DescribeTableOutput, error) {
+	return func(input *dynamoDBLib.DescribeTableInput) (*dynamoDBLib.DescribeTableOutput, error) {
+		keySchema := make([]*dynamoDBLib.KeySchemaElement, 0, len(names))
+		for _, name := range names {
+			keySchema = append(keySchema, &dynamoDBLib.KeySchemaElement{
+				AttributeName: aws.String(name),
+			})
+		}
+
+		return &dynamoDBLib.DescribeTableOutput{
+			Table: &dynamoDBLib.TableDescription{
+				KeySchema: keySchema,
+				TableName: input.TableName,
+			},
+		}, nil
+	}
+}
+
+func copyWriteRequest(request *dynamoDBLib.WriteRequest) *dynamoDBLib.WriteRequest {
+	var copied dynamoDBLib.WriteRequest
+	if request.PutRequest != nil {
+		item := make(map[string]*dynamoDBLib.AttributeValue)
+		for name, value := range request.PutRequest.Item {
+			item[name] = &dynamoDBLib.AttributeValue{S: aws.String(*value.S)}
+		}
+
+		copied.PutRequest = &dynamoDBLib.PutRequest{Item: item}
+	}
+
+	if request.DeleteRequest != nil {
+		key := make(map[string]*dynamoDBLib.AttributeValue)
+		for name, value := range request.DeleteRequest.Key {
+			key[name] = &dynamoDBLib.AttributeValue{S: aws.String(*value.S)}
+		}
+
+		copied.DeleteRequest = &dynamoDBLib.DeleteRequest{Key: key}
+	}
+
+	return &copied
+}
+
+func generatePuts(tableName string, count int) []dynamodb.Marshaler {
+	puts := make([]dynamodb.Marshaler, 0, count)
+	for i := 0; i < count; i++ {
+		puts = append(puts, TestWriteModel{fmt.Sprintf("put_%d", i), tableName})
+	}
+
+	return puts
+}
+
+func generateDeletes(tableName string, count int) []dynamodb.Deletable {
+	deletes := make([]dynamodb.Deletable, 0, count)
+	for i := 0; i < count; i++ {
+		deletes = append(deletes, TestWriteModel{fmt.Sprintf("delete_%d", i), tableName})
+	}
+
+	return deletes
+}
@@ -1,7 +1,9 @@
 package dynamodb
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
 	"net"
 	"net/url"
 	"runtime"
@@ -14,50 +16,63 @@ import (
 	dynamoDBLib "github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/vidsy/awswrappers"
 	"github.com/vidsy/backoff"
 )
 
 const (
-	batchGetMaxItems = 100
+	batchGetMaxItems         = 100
+	batchGetMaxConcurrency   = 10
+	batchWriteMaxItems       = 25
+	batchWriteMaxConcurrency = 10
 )
 
 type (
 	// ClientWrapper interface for client wrapping DynamoDB.
 	ClientWrapper interface{}
 
+	// ScanPageFunc is called by ScanStream with the items of each page.
+	ScanPageFunc func(items []map[string]*dynamoDBLib.AttributeValue) error
+
 	// Client wraps a selection of functions of the DynamoDB client.
 	Client struct {
 		dynamodbiface.DynamoDBAPI
-		clientConfig *ClientConfig
+		clientConfig          *ClientConfig
+		logger                awswrappers.Logger
+		tracer                awswrappers.Tracer
+		healthCheckTables     []string
+		batchGetConcurrency   int
+		batchWriteConcurrency int
 	}
 )
 
 var (
+	batchGetRetryIntervals      = []int{0, 50, 100, 200, 400}
+	batchWriteRetryIntervals    = []int{0, 50, 100, 200, 400}
 	developmentBackoffIntervals = []int{0, 500, 1000, 2000, 4000, 8000, 16000, 32000}
 )
 
-// NewClient creates a new wrapper based on the environment.
+// NewClient creates a new wrapper based on the environment, see
+// NewClientWithOptions for more settings.
 func NewClient(config *ClientConfig, isDevelopment bool, developmentLogMessageHandler func(string), client dynamodbiface.DynamoDBAPI) (*Client, error) {
-	if client == nil {
-		client = dynamoDBLib.New(session.New())
-
-		if isDevelopment {
-			err := testConnection(config.DynamoDBEndpoint, developmentBackoffIntervals, developmentLogMessageHandler)
-			if err != nil {
-				return nil, err
-			}
-
-			client = dynamoDBLib.New(
-				session.New(),
-				aws.NewConfig().WithEndpoint(config.DynamoDBEndpoint),
-			)
-		}
-	}
+	return NewClientWithOptions(
+		WithAPI(client),
+		WithClientConfig(config),
+		WithDevelopmentMode(isDevelopment),
+		withDevelopmentLogMessageHandler(developmentLogMessageHandler),
+	)
+}
 
-	return &Client{
-		client,
-		config,
-	}, nil
+// NewClientWithSession creates a new wrapper using the given session, see
+// awswrappers.NewSession. In development mode it waits for the local
+// endpoint to accept connections.
+func NewClientWithSession(config *ClientConfig, isDevelopment bool, developmentLogMessageHandler func(string), sess *session.Session) (*Client, error) {
+	return NewClientWithOptions(
+		WithClientConfig(config),
+		WithDevelopmentMode(isDevelopment),
+		withDevelopmentLogMessageHandler(developmentLogMessageHandler),
+		WithSession(sess),
+	)
 }
 
 func testConnection(endpoint string, backoffIntervals []int, developmentLogMessageHandler func(string)) error {
@@ -87,58 +102,73 @@ func testConnection(endpoint string, backoffIntervals []int, developmentLogMessa
 	return nil
 }
 
-// BatchGetItem extends the default clients BatchGetItem.
+// BatchGetItem extends the default clients BatchGetItem, fetching the keys in
+// parallel chunks of 100 and retrying the keys DynamoDB leaves unprocessed
+// with backoff. Keys that are still unprocessed are returned in an
+// UnprocessedKeysError.
 func (c Client) BatchGetItem(tableName string, batchGetItem BatchGetItem, bindModel interface{}) error {
-	attributeValues := marshalValuesIntoAttributeValues(batchGetItem)
-	results := make([]map[string]*dynamoDBLib.AttributeValue, 0)
+	return c.BatchGetItemWithContext(context.Background(), tableName, batchGetItem, bindModel)
+}
 
-	for i := 0; i < len(attributeValues); i += batchGetMaxItems {
-		end := i + batchGetMaxItems
+// BatchGetItemWithContext is BatchGetItem with a context that can cancel the
+// requests.
+func (c Client) BatchGetItemWithContext(ctx context.Context, tableName string, batchGetItem BatchGetItem, bindModel interface{}) error {
+	ctx, span := c.tracer.StartSpan(ctx, "dynamodb.BatchGetItem")
+	span.SetAttribute("table", tableName)
+	span.SetAttribute("keys", len(batchGetItem))
 
-		if end > len(attributeValues) {
-			end = len(attributeValues)
-		}
+	err := c.batchGetItem(ctx, tableName, batchGetItem, bindModel)
+	awswrappers.EndSpan(span, err)
 
-		batchGetItemInput := &dynamoDBLib.BatchGetItemInput{
-			RequestItems: map[string]*dynamoDBLib.KeysAndAttributes{
-				tableName: {
-					Keys: attributeValues[i:end],
-				},
-			},
-		}
+	return err
+}
 
-		output, err := c.DynamoDBAPI.BatchGetItem(batchGetItemInput)
-		if err != nil {
-			return errors.Wrapf(
-				err,
-				"Error fetching BatchGetItem table '%s', range %d:%d",
-				tableName,
-				i,
-				end,
-			)
-		}
+func (c Client) batchGetItem(ctx context.Context, tableName string, batchGetItem BatchGetItem, bindModel interface{}) error {
+	requestItems := map[string]*dynamoDBLib.KeysAndAttributes{
+		tableName: {
+			Keys: marshalValuesIntoAttributeValues(batchGetItem),
+		},
+	}
 
-		results = append(results, output.Responses[tableName]...)
+	responses, unprocessedKeys, err := c.batchGet(ctx, requestItems)
+	if err != nil {
+		return err
 	}
 
-	err := dynamodbattribute.UnmarshalListOfMaps(results, &bindModel)
+	err = dynamodbattribute.UnmarshalListOfMaps(responses[tableName], &bindModel)
 	if err != nil {
 		return err
 	}
 
+	if len(unprocessedKeys) > 0 {
+		return &UnprocessedKeysError{Keys: unprocessedKeys}
+	}
+
 	return nil
 }
 
 // DeleteItem extends the default clients DeleteItem taking a struct that implements
 // the Deletable interface.
 func (c Client) DeleteItem(item Deletable) (*dynamoDBLib.DeleteItemOutput, error) {
+	return c.DeleteItemWithContext(context.Background(), item)
+}
+
+// DeleteItemWithContext is DeleteItem with a context that can cancel the
+// request.
+func (c Client) DeleteItemWithContext(ctx context.Context, item Deletable) (*dynamoDBLib.DeleteItemOutput, error) {
+	ctx, span := c.tracer.StartSpan(ctx, "dynamodb.DeleteItem")
+	span.SetAttribute("table", item.TableName())
+
 	key, err := dynamodbattribute.MarshalMap(item.Key())
 	if err != nil {
-		return nil, errors.Wrapf(
+		err = errors.Wrapf(
 			err,
 			"Problem marshaling key:%s to AttributeValue.",
 			key,
 		)
+		awswrappers.EndSpan(span, err)
+
+		return nil, err
 	}
 
 	deleteItemInput := &dynamoDBLib.DeleteItemInput{
@@ -146,94 +176,176 @@ func (c Client) DeleteItem(item Deletable) (*dynamoDBLib.DeleteItemOutput, error
 		TableName: aws.String(item.TableName()),
 	}
 
-	return c.DynamoDBAPI.DeleteItem(deleteItemInput)
+	output, err := c.DynamoDBAPI.DeleteItemWithContext(ctx, deleteItemInput)
+	awswrappers.EndSpan(span, err)
+
+	return output, err
 }
 
 // PutItem extends the default clients PutItem taking a struct that implements
 // the marshaler interface.
 func (c Client) PutItem(item Marshaler) (*dynamoDBLib.PutItemOutput, error) {
+	return c.PutItemWithContext(context.Background(), item)
+}
+
+// PutItemWithContext is PutItem with a context that can cancel the request.
+func (c Client) PutItemWithContext(ctx context.Context, item Marshaler) (*dynamoDBLib.PutItemOutput, error) {
+	ctx, span := c.tracer.StartSpan(ctx, "dynamodb.PutItem")
+
 	putItemInput, err := item.Marshal()
 	if err != nil {
+		awswrappers.EndSpan(span, err)
 		return nil, err
 	}
 
-	return c.DynamoDBAPI.PutItem(putItemInput)
+	span.SetAttribute("table", aws.StringValue(putItemInput.TableName))
+
+	output, err := c.DynamoDBAPI.PutItemWithContext(ctx, putItemInput)
+	awswrappers.EndSpan(span, err)
+
+	return output, err
 }
 
 // Query extends the default clients Query and takes the query params and
-// struct to unmarshal the data into.
+// struct to unmarshal the data into. Only a single page is read, see
+// QueryAll and NewQueryIterator for results over 1MB.
 func (c Client) Query(input *dynamoDBLib.QueryInput, bindModel interface{}) (*dynamoDBLib.QueryOutput, error) {
-	output, err := c.DynamoDBAPI.Query(input)
-	if err != nil {
-		return output, err
-	}
+	return c.QueryWithContext(context.Background(), input, bindModel)
+}
 
-	err = dynamodbattribute.UnmarshalListOfMaps(output.Items, &bindModel)
-	if err != nil {
-		return output, err
+// QueryWithContext is Query with a context that can cancel the request.
+func (c Client) QueryWithContext(ctx context.Context, input *dynamoDBLib.QueryInput, bindModel interface{}) (*dynamoDBLib.QueryOutput, error) {
+	ctx, span := c.tracer.StartSpan(ctx, "dynamodb.Query")
+	span.SetAttribute("table", aws.StringValue(input.TableName))
+
+	output, err := c.DynamoDBAPI.QueryWithContext(ctx, input)
+	if err == nil {
+		err = dynamodbattribute.UnmarshalListOfMaps(output.Items, &bindModel)
 	}
 
-	return output, nil
+	awswrappers.EndSpan(span, err)
+
+	return output, err
 }
 
 // Scan extends the underlying scan with pages functionality and automatically
 // creates a set of parellel requests and binds the result to the given struct
-// or returns an error.
+// or returns an error. Every page of each segment is read, when
+// TotalSegments isn't set a segment is scanned per CPU. The first error stops
+// the other segments and is returned.
 func (c Client) Scan(params dynamoDBLib.ScanInput, bindModel interface{}) error {
-	errChan := make(chan error)
-	itemsChan := make(chan map[string]*dynamoDBLib.AttributeValue)
+	return c.ScanWithContext(context.Background(), params, bindModel)
+}
+
+// ScanWithContext is Scan with a context that can cancel the requests.
+func (c Client) ScanWithContext(ctx context.Context, params dynamoDBLib.ScanInput, bindModel interface{}) error {
+	ctx, span := c.tracer.StartSpan(ctx, "dynamodb.Scan")
+	span.SetAttribute("table", aws.StringValue(params.TableName))
+
+	err := c.scan(ctx, params, bindModel)
+	awswrappers.EndSpan(span, err)
+
+	return err
+}
+
+// ScanStream is Scan passing the items of each page to pageFunc instead of
+// binding them, so the table doesn't have to fit in memory. pageFunc is
+// never called concurrently, an error returned by it stops the scan and is
+// returned.
+func (c Client) ScanStream(params dynamoDBLib.ScanInput, pageFunc ScanPageFunc) error {
+	return c.ScanStreamWithContext(context.Background(), params, pageFunc)
+}
+
+// ScanStreamWithContext is ScanStream with a context that can cancel the
+// requests.
+func (c Client) ScanStreamWithContext(ctx context.Context, params dynamoDBLib.ScanInput, pageFunc ScanPageFunc) error {
+	ctx, span := c.tracer.StartSpan(ctx, "dynamodb.ScanStream")
+	span.SetAttribute("table", aws.StringValue(params.TableName))
+
+	err := c.scanSegments(ctx, params, pageFunc)
+	awswrappers.EndSpan(span, err)
+
+	return err
+}
+
+func (c Client) scan(ctx context.Context, params dynamoDBLib.ScanInput, bindModel interface{}) error {
 	items := []map[string]*dynamoDBLib.AttributeValue{}
-	var scanQueriesWaitGroup sync.WaitGroup
 
-	if *params.TotalSegments == 0 {
-		params.TotalSegments = aws.Int64(int64(runtime.NumCPU()))
+	err := c.scanSegments(ctx, params, func(pageItems []map[string]*dynamoDBLib.AttributeValue) error {
+		items = append(items, pageItems...)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	scanQueriesWaitGroup.Add(int(*params.TotalSegments))
+	return dynamodbattribute.UnmarshalListOfMaps(items, &bindModel)
+}
 
-	for i := int64(0); i < *params.TotalSegments; i++ {
-		go c.scanWorker(params, itemsChan, errChan, i, &scanQueriesWaitGroup)
+// scanSegments scans every page of each segment in parallel, passing the
+// pages to pageFunc one at a time. The first error cancels the other
+// segments.
+func (c Client) scanSegments(ctx context.Context, params dynamoDBLib.ScanInput, pageFunc ScanPageFunc) error {
+	totalSegments := int64(runtime.NumCPU())
+	if params.TotalSegments != nil && *params.TotalSegments > 0 {
+		totalSegments = *params.TotalSegments
 	}
 
-	go func(errChan chan error) {
-		scanQueriesWaitGroup.Wait()
-		errChan <- nil
-	}(errChan)
+	params.TotalSegments = aws.Int64(totalSegments)
 
-	for {
-		select {
-		case item := <-itemsChan:
-			items = append(items, item)
-		case err := <-errChan:
-			if err != nil {
-				return err
-			}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var firstErr error
+	var firstErrOnce sync.Once
+	var pageMutex sync.Mutex
+	var segmentsWaitGroup sync.WaitGroup
+
+	segmentsWaitGroup.Add(int(totalSegments))
+
+	for segment := int64(0); segment < totalSegments; segment++ {
+		go func(segment int64) {
+			defer segmentsWaitGroup.Done()
+
+			err := c.scanSegment(ctx, params, segment, func(items []map[string]*dynamoDBLib.AttributeValue) error {
+				pageMutex.Lock()
+				defer pageMutex.Unlock()
+
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+
+				return pageFunc(items)
+			})
 
-			err = dynamodbattribute.UnmarshalListOfMaps(items, &bindModel)
 			if err != nil {
-				return err
+				firstErrOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
 			}
-
-			return nil
-		}
+		}(segment)
 	}
+
+	segmentsWaitGroup.Wait()
+
+	return firstErr
 }
 
-func (c Client) scanWorker(params dynamoDBLib.ScanInput, itemsChan chan map[string]*dynamoDBLib.AttributeValue, errChan chan error, segment int64, scanQueriesWaitGroup *sync.WaitGroup) {
-	defer scanQueriesWaitGroup.Done()
+func (c Client) scanSegment(ctx context.Context, params dynamoDBLib.ScanInput, segment int64, pageFunc ScanPageFunc) error {
+	var pageErr error
 	params.Segment = aws.Int64(segment)
 
-	err := c.DynamoDBAPI.ScanPages(&params, func(result *dynamoDBLib.ScanOutput, lastPage bool) bool {
-		for _, item := range result.Items {
-			itemsChan <- item
-		}
-
-		return lastPage
+	err := c.DynamoDBAPI.ScanPagesWithContext(ctx, &params, func(result *dynamoDBLib.ScanOutput, lastPage bool) bool {
+		pageErr = pageFunc(result.Items)
+		return pageErr == nil
 	})
 
-	if err != nil {
-		errChan <- err
+	if pageErr != nil {
+		return pageErr
 	}
+
+	return err
 }
 
 func marshalValuesIntoAttributeValues(batchGetItem BatchGetItem) []map[string]*dynamoDBLib.AttributeValue {
@@ -243,11 +355,13 @@ func marshalValuesIntoAttributeValues(batchGetItem BatchGetItem) []map[string]*d
 		for _, attributeValue := range attributeValues {
 			attributeKeyValue := make(map[string]*dynamoDBLib.AttributeValue)
 			switch castValue := attributeValue.(type) {
-			case int, int8, int16, int32, int64, float32, float64:
-				stringNumberValue := fmt.Sprintf("%s", castValue)
+			case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+				stringNumberValue := fmt.Sprintf("%v", castValue)
 				attributeKeyValue[primaryKey] = &dynamoDBLib.AttributeValue{N: aws.String(stringNumberValue)}
 			case string:
 				attributeKeyValue[primaryKey] = &dynamoDBLib.AttributeValue{S: aws.String(castValue)}
+			case []byte:
+				attributeKeyValue[primaryKey] = &dynamoDBLib.AttributeValue{B: castValue}
 			}
 
 			attributeKeyValueSlice = append(
@@ -259,3 +373,14 @@ func marshalValuesIntoAttributeValues(batchGetItem BatchGetItem) []map[string]*d
 
 	return attributeKeyValueSlice
 }
+
+// jitteredIntervals spreads each backoff interval between half and all of
+// its length, so concurrent retries don't hit DynamoDB at the same time.
+func jitteredIntervals(intervals []int) []int {
+	jittered := make([]int, len(intervals))
+	for i, interval := range intervals {
+		jittered[i] = interval/2 + rand.Intn(interval/2+1)
+	}
+
+	return jittered
+}
@@ -1,12 +1,17 @@
 package dynamodb_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	dynamoDBLib "github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/stretchr/testify/assert"
@@ -16,8 +21,12 @@ import (
 type (
 	MockSDKClient struct {
 		dynamodbiface.DynamoDBAPI
-		mockBatchGetItem func(*dynamoDBLib.BatchGetItemInput) (*dynamoDBLib.BatchGetItemOutput, error)
-		mockScanPages    func(*dynamoDBLib.ScanInput, func(*dynamoDBLib.ScanOutput, bool) bool) error
+		mockBatchGetItem   func(*dynamoDBLib.BatchGetItemInput) (*dynamoDBLib.BatchGetItemOutput, error)
+		mockBatchWriteItem func(*dynamoDBLib.BatchWriteItemInput) (*dynamoDBLib.BatchWriteItemOutput, error)
+		mockDescribeTable  func(*dynamoDBLib.DescribeTableInput) (*dynamoDBLib.DescribeTableOutput, error)
+		mockListTables     func(*dynamoDBLib.ListTablesInput) (*dynamoDBLib.ListTablesOutput, error)
+		mockQuery          func(*dynamoDBLib.QueryInput) (*dynamoDBLib.QueryOutput, error)
+		mockScanPages      func(*dynamoDBLib.ScanInput, func(*dynamoDBLib.ScanOutput, bool) bool) error
 	}
 
 	TestModel struct {
@@ -29,7 +38,47 @@ type (
 	}
 )
 
-func (m MockSDKClient) BatchGetItem(batchGetItem *dynamoDBLib.BatchGetItemInput) (*dynamoDBLib.BatchGetItemOutput, error) {
+func (m MockSDKClient) BatchWriteItemWithContext(ctx aws.Context, input *dynamoDBLib.BatchWriteItemInput, opts ...request.Option) (*dynamoDBLib.BatchWriteItemOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.mockBatchWriteItem != nil {
+		return m.mockBatchWriteItem(input)
+	}
+
+	return &dynamoDBLib.BatchWriteItemOutput{}, nil
+}
+
+func (m MockSDKClient) DescribeTableWithContext(ctx aws.Context, input *dynamoDBLib.DescribeTableInput, opts ...request.Option) (*dynamoDBLib.DescribeTableOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.mockDescribeTable != nil {
+		return m.mockDescribeTable(input)
+	}
+
+	return nil, nil
+}
+
+func (m MockSDKClient) ListTablesWithContext(ctx aws.Context, input *dynamoDBLib.ListTablesInput, opts ...request.Option) (*dynamoDBLib.ListTablesOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.mockListTables != nil {
+		return m.mockListTables(input)
+	}
+
+	return nil, nil
+}
+
+func (m MockSDKClient) BatchGetItemWithContext(ctx aws.Context, batchGetItem *dynamoDBLib.BatchGetItemInput, opts ...request.Option) (*dynamoDBLib.BatchGetItemOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	if m.mockBatchGetItem != nil {
 		return m.mockBatchGetItem(batchGetItem)
 	}
@@ -37,7 +86,41 @@ func (m MockSDKClient) BatchGetItem(batchGetItem *dynamoDBLib.BatchGetItemInput)
 	return nil, nil
 }
 
-func (m MockSDKClient) ScanPages(input *dynamoDBLib.ScanInput, pageFunc func(*dynamoDBLib.ScanOutput, bool) bool) error {
+func (m MockSDKClient) QueryWithContext(ctx aws.Context, input *dynamoDBLib.QueryInput, opts ...request.Option) (*dynamoDBLib.QueryOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.mockQuery != nil {
+		return m.mockQuery(input)
+	}
+
+	return &dynamoDBLib.QueryOutput{}, nil
+}
+
+func (m MockSDKClient) QueryPagesWithContext(ctx aws.Context, input *dynamoDBLib.QueryInput, pageFunc func(*dynamoDBLib.QueryOutput, bool) bool, opts ...request.Option) error {
+	queryInput := *input
+
+	for {
+		output, err := m.QueryWithContext(ctx, &queryInput)
+		if err != nil {
+			return err
+		}
+
+		lastPage := len(output.LastEvaluatedKey) == 0
+		if !pageFunc(output, lastPage) || lastPage {
+			return nil
+		}
+
+		queryInput.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+func (m MockSDKClient) ScanPagesWithContext(ctx aws.Context, input *dynamoDBLib.ScanInput, pageFunc func(*dynamoDBLib.ScanOutput, bool) bool, opts ...request.Option) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
 	if m.mockScanPages != nil {
 		return m.mockScanPages(input, pageFunc)
 	}
@@ -69,10 +152,13 @@ func TestClient(t *testing.T) {
 		}
 
 		t.Run("GeneratesSegmentedQuery", func(t *testing.T) {
+			var mutex sync.Mutex
 			queryCount := 0
 			mockSDKClient := &MockSDKClient{
 				mockScanPages: func(input *dynamoDBLib.ScanInput, pageFunc func(*dynamoDBLib.ScanOutput, bool) bool) error {
+					mutex.Lock()
 					queryCount++
+					mutex.Unlock()
 
 					output := &dynamoDBLib.ScanOutput{
 						Items: []map[string]*dynamoDBLib.AttributeValue{},
@@ -135,6 +221,121 @@ func TestClient(t *testing.T) {
 			err = testClient.Scan(params, nil)
 			assert.NotNil(t, err)
 		})
+
+		t.Run("ReturnsErrorWhenContextCancelled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			testClient, err := NewTestClient(nil)
+			assert.Nil(t, err)
+
+			err = testClient.ScanWithContext(ctx, params, nil)
+			assert.Equal(t, context.Canceled, err)
+		})
+
+		t.Run("DefaultsTotalSegments", func(t *testing.T) {
+			var mutex sync.Mutex
+			queryCount := 0
+
+			mockSDKClient := &MockSDKClient{
+				mockScanPages: func(input *dynamoDBLib.ScanInput, pageFunc func(*dynamoDBLib.ScanOutput, bool) bool) error {
+					mutex.Lock()
+					queryCount++
+					mutex.Unlock()
+
+					pageFunc(&dynamoDBLib.ScanOutput{}, true)
+					return nil
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			err = testClient.Scan(dynamoDBLib.ScanInput{TableName: aws.String("message_group")}, nil)
+
+			assert.Nil(t, err)
+			assert.Equal(t, runtime.NumCPU(), queryCount)
+		})
+
+		t.Run("ReadsEveryPage", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{mockScanPages: pagedScan(3)}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			var testModels []TestModel
+			err = testClient.Scan(params, &testModels)
+
+			assert.Nil(t, err)
+			assert.Len(t, testModels, 12)
+		})
+
+		t.Run("StopsOtherSegmentsOnError", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{
+				mockScanPages: func(input *dynamoDBLib.ScanInput, pageFunc func(*dynamoDBLib.ScanOutput, bool) bool) error {
+					if *input.Segment == 0 {
+						return errors.New("Segment error")
+					}
+
+					for pageFunc(&dynamoDBLib.ScanOutput{}, false) {
+						time.Sleep(time.Millisecond)
+					}
+
+					return nil
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			err = testClient.Scan(params, nil)
+			assert.EqualError(t, err, "Segment error")
+		})
+	})
+
+	t.Run(".ScanStream()", func(t *testing.T) {
+		params := dynamoDBLib.ScanInput{
+			TableName:     aws.String("message_group"),
+			TotalSegments: aws.Int64(4),
+		}
+
+		t.Run("PassesEachPage", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{mockScanPages: pagedScan(3)}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			pages := 0
+			items := 0
+
+			err = testClient.ScanStream(params, func(pageItems []map[string]*dynamoDBLib.AttributeValue) error {
+				pages++
+				items += len(pageItems)
+
+				return nil
+			})
+
+			assert.Nil(t, err)
+			assert.Equal(t, 12, pages)
+			assert.Equal(t, 12, items)
+		})
+
+		t.Run("ReturnsPageFuncError", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{mockScanPages: pagedScan(3)}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			pages := 0
+
+			err = testClient.ScanStream(params, func(pageItems []map[string]*dynamoDBLib.AttributeValue) error {
+				pages++
+				return errors.New("Page error")
+			})
+
+			assert.EqualError(t, err, "Page error")
+			assert.Equal(t, 1, pages)
+		})
 	})
 
 	t.Run(".BatchGetItem()", func(t *testing.T) {
@@ -203,10 +404,13 @@ func TestClient(t *testing.T) {
 				tableName, attributeKeyValuesPage2,
 			)
 
+			var mutex sync.Mutex
 			callCount := 0
 			mockSDKClient := &MockSDKClient{
 				mockBatchGetItem: func(input *dynamoDBLib.BatchGetItemInput) (*dynamoDBLib.BatchGetItemOutput, error) {
+					mutex.Lock()
 					callCount++
+					mutex.Unlock()
 
 					if len(input.RequestItems[tableName].Keys) == 100 {
 						return &batchItemOutputPage1, nil
@@ -247,6 +451,28 @@ func TestClient(t *testing.T) {
 	})
 }
 
+// pagedScan returns a scan with a single item on each of the given number of
+// pages for every segment, stopping when pageFunc returns false.
+func pagedScan(pages int) func(*dynamoDBLib.ScanInput, func(*dynamoDBLib.ScanOutput, bool) bool) error {
+	return func(input *dynamoDBLib.ScanInput, pageFunc func(*dynamoDBLib.ScanOutput, bool) bool) error {
+		for page := 0; page < pages; page++ {
+			fooValue := fmt.Sprintf("foo_%d_%d", *input.Segment, page)
+
+			output := &dynamoDBLib.ScanOutput{
+				Items: []map[string]*dynamoDBLib.AttributeValue{
+					{"foo": {S: aws.String(fooValue)}},
+				},
+			}
+
+			if !pageFunc(output, page == pages-1) {
+				return nil
+			}
+		}
+
+		return nil
+	}
+}
+
 func buildBatchGetItemOutput(tableName string, attributeKeyValues map[string][]interface{}) dynamoDBLib.BatchGetItemOutput {
 	responses := make(map[string][]map[string]*dynamoDBLib.AttributeValue)
 	responses[tableName] = make([]map[string]*dynamoDBLib.AttributeValue, 0)
@@ -0,0 +1,35 @@
+package dynamodb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	dynamoDBLib "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/pkg/errors"
+)
+
+// HealthCheck checks DynamoDB can be reached, describing the tables set with
+// WithHealthCheckTables or listing a table when none are set.
+func (c Client) HealthCheck(ctx context.Context) error {
+	if len(c.healthCheckTables) == 0 {
+		_, err := c.DynamoDBAPI.ListTablesWithContext(ctx, &dynamoDBLib.ListTablesInput{
+			Limit: aws.Int64(1),
+		})
+		if err != nil {
+			return errors.Wrap(err, "Unable to list DynamoDB tables")
+		}
+
+		return nil
+	}
+
+	for _, tableName := range c.healthCheckTables {
+		_, err := c.DynamoDBAPI.DescribeTableWithContext(ctx, &dynamoDBLib.DescribeTableInput{
+			TableName: aws.String(tableName),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Unable to describe DynamoDB table '%s'", tableName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package dynamodb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dynamoDBLib "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/dynamodb"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run(".HealthCheck()", func(t *testing.T) {
+		t.Run("ListsTablesWithoutHealthCheckTables", func(t *testing.T) {
+			var limit int64
+
+			mock := MockSDKClient{
+				mockListTables: func(input *dynamoDBLib.ListTablesInput) (*dynamoDBLib.ListTablesOutput, error) {
+					limit = *input.Limit
+					return &dynamoDBLib.ListTablesOutput{}, nil
+				},
+			}
+
+			client, err := NewTestClient(&mock)
+			assert.NoError(t, err)
+
+			err = client.HealthCheck(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1), limit)
+		})
+
+		t.Run("DescribesHealthCheckTables", func(t *testing.T) {
+			var tableNames []string
+
+			mock := MockSDKClient{
+				mockDescribeTable: func(input *dynamoDBLib.DescribeTableInput) (*dynamoDBLib.DescribeTableOutput, error) {
+					tableNames = append(tableNames, *input.TableName)
+					return &dynamoDBLib.DescribeTableOutput{}, nil
+				},
+			}
+
+			client, err := dynamodb.NewClientWithOptions(
+				dynamodb.WithAPI(&mock),
+				dynamodb.WithHealthCheckTables("foo", "bar"),
+			)
+			assert.NoError(t, err)
+
+			err = client.HealthCheck(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"foo", "bar"}, tableNames)
+		})
+
+		t.Run("ReturnsErrorForMissingTable", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockDescribeTable: func(input *dynamoDBLib.DescribeTableInput) (*dynamoDBLib.DescribeTableOutput, error) {
+					return nil, errors.New("Table not found")
+				},
+			}
+
+			client, err := dynamodb.NewClientWithOptions(
+				dynamodb.WithAPI(&mock),
+				dynamodb.WithHealthCheckTables("foo"),
+			)
+			assert.NoError(t, err)
+
+			err = client.HealthCheck(context.Background())
+
+			assert.EqualError(t, err, "Unable to describe DynamoDB table 'foo': Table not found")
+		})
+	})
+}
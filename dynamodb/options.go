@@ -0,0 +1,210 @@
+package dynamodb
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	dynamoDBLib "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/vidsy/awswrappers"
+)
+
+type (
+	// Option configures a Client created by NewClientWithOptions.
+	Option func(*options)
+
+	options struct {
+		api                   dynamodbiface.DynamoDBAPI
+		batchGetConcurrency   int
+		batchWriteConcurrency int
+		config                *ClientConfig
+		developmentMode       bool
+		endpoint              string
+		healthCheckTables     []string
+		logMessageHandler     func(string)
+		logger                awswrappers.Logger
+		metrics               awswrappers.Metrics
+		session               *session.Session
+		tracer                awswrappers.Tracer
+	}
+)
+
+// WithAPI uses the given DynamoDB API instead of creating one, the session
+// and endpoint options are ignored.
+func WithAPI(api dynamodbiface.DynamoDBAPI) Option {
+	return func(o *options) {
+		o.api = api
+	}
+}
+
+// WithBatchGetConcurrency limits the number of chunks of 100 keys fetched
+// in parallel by Client.BatchGetItem, the default is 10.
+func WithBatchGetConcurrency(limit int) Option {
+	return func(o *options) {
+		o.batchGetConcurrency = limit
+	}
+}
+
+// WithBatchWriteConcurrency limits the number of chunks of 25 items written
+// in parallel by Client.BatchWrite, the default is 10.
+func WithBatchWriteConcurrency(limit int) Option {
+	return func(o *options) {
+		o.batchWriteConcurrency = limit
+	}
+}
+
+// WithClientConfig sets the config of the Client.
+func WithClientConfig(config *ClientConfig) Option {
+	return func(o *options) {
+		o.config = config
+	}
+}
+
+// WithDevelopmentMode sends requests to ClientConfig.DynamoDBEndpoint,
+// waiting for it to accept connections first.
+func WithDevelopmentMode(developmentMode bool) Option {
+	return func(o *options) {
+		o.developmentMode = developmentMode
+	}
+}
+
+// WithEndpoint sends requests to the given endpoint, taking precedence over
+// ClientConfig.DynamoDBEndpoint in development mode.
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithHealthCheckTables sets the tables described by Client.HealthCheck.
+func WithHealthCheckTables(names ...string) Option {
+	return func(o *options) {
+		o.healthCheckTables = names
+	}
+}
+
+// WithLogger logs the requests made by the Client, its retries and the
+// attempts to connect to the development endpoint to the given logger.
+// Requests are only logged when the API isn't given with WithAPI.
+func WithLogger(logger awswrappers.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// withDevelopmentLogMessageHandler keeps the messages of NewClient's
+// developmentLogMessageHandler to the connection attempts.
+func withDevelopmentLogMessageHandler(logMessageHandler func(string)) Option {
+	return func(o *options) {
+		o.logMessageHandler = logMessageHandler
+	}
+}
+
+// WithMetrics records the requests made by the Client to the given
+// metrics, it has no effect when the API is given with WithAPI.
+func WithMetrics(metrics awswrappers.Metrics) Option {
+	return func(o *options) {
+		o.metrics = metrics
+	}
+}
+
+// WithSession creates the DynamoDB API from the given session, see
+// awswrappers.NewSession.
+func WithSession(sess *session.Session) Option {
+	return func(o *options) {
+		o.session = sess
+	}
+}
+
+// WithTracer starts a span for each call made with the Client, see
+// awswrappers.Tracer.
+func WithTracer(tracer awswrappers.Tracer) Option {
+	return func(o *options) {
+		o.tracer = tracer
+	}
+}
+
+// NewClientWithOptions creates a new wrapper from the given options, by
+// default an empty ClientConfig and a new default session are used.
+func NewClientWithOptions(opts ...Option) (*Client, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.config == nil {
+		o.config = &ClientConfig{}
+	}
+
+	if o.batchGetConcurrency <= 0 {
+		o.batchGetConcurrency = batchGetMaxConcurrency
+	}
+
+	if o.batchWriteConcurrency <= 0 {
+		o.batchWriteConcurrency = batchWriteMaxConcurrency
+	}
+
+	logger := o.logger
+	if logger == nil {
+		logger = awswrappers.NopLogger{}
+	}
+
+	tracer := o.tracer
+	if tracer == nil {
+		tracer = awswrappers.NopTracer{}
+	}
+
+	if o.api == nil {
+		if o.session == nil {
+			o.session = session.New()
+		}
+
+		endpoint := o.endpoint
+		if endpoint == "" && o.developmentMode {
+			endpoint = o.config.DynamoDBEndpoint
+		}
+
+		if o.developmentMode {
+			logMessageHandler := o.logMessageHandler
+			if logMessageHandler == nil {
+				logMessageHandler = func(message string) {
+					logger.Log(message, awswrappers.Fields{
+						"endpoint": endpoint,
+						"service":  dynamoDBLib.ServiceName,
+					})
+				}
+			}
+
+			err := testConnection(endpoint, developmentBackoffIntervals, logMessageHandler)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var dynamoDBClient *dynamoDBLib.DynamoDB
+
+		if endpoint != "" {
+			dynamoDBClient = dynamoDBLib.New(o.session, awswrappers.DevelopmentConfig(endpoint))
+		} else {
+			dynamoDBClient = dynamoDBLib.New(o.session)
+		}
+
+		if o.logger != nil {
+			awswrappers.LogRequests(&dynamoDBClient.Handlers, o.logger)
+		}
+
+		if o.metrics != nil {
+			awswrappers.RecordRequests(&dynamoDBClient.Handlers, o.metrics)
+		}
+
+		o.api = dynamoDBClient
+	}
+
+	return &Client{
+		o.api,
+		o.config,
+		logger,
+		tracer,
+		o.healthCheckTables,
+		o.batchGetConcurrency,
+		o.batchWriteConcurrency,
+	}, nil
+}
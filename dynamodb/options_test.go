@@ -0,0 +1,28 @@
+package dynamodb_test
+
+import (
+	"testing"
+
+	dynamoDBLib "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/dynamodb"
+)
+
+func TestOptions(t *testing.T) {
+	t.Run(".NewClientWithOptions()", func(t *testing.T) {
+		t.Run("UsesGivenAPI", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{}
+			client, err := dynamodb.NewClientWithOptions(dynamodb.WithAPI(mockSDKClient))
+
+			assert.Nil(t, err)
+			assert.Equal(t, mockSDKClient, client.DynamoDBAPI)
+		})
+
+		t.Run("UsesEndpoint", func(t *testing.T) {
+			client, err := dynamodb.NewClientWithOptions(dynamodb.WithEndpoint("http://localhost:8000"))
+
+			assert.Nil(t, err)
+			assert.Equal(t, "http://localhost:8000", client.DynamoDBAPI.(*dynamoDBLib.DynamoDB).Endpoint)
+		})
+	})
+}
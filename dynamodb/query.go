@@ -0,0 +1,205 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go/aws"
+	dynamoDBLib "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/pkg/errors"
+	"github.com/vidsy/awswrappers"
+)
+
+type (
+	// QueryIterator iterates over the pages of a query one request at a
+	// time, see Client.NewQueryIterator.
+	QueryIterator struct {
+		client Client
+		ctx    context.Context
+		err    error
+		input  dynamoDBLib.QueryInput
+		output *dynamoDBLib.QueryOutput
+	}
+
+	cursorValue struct {
+		B []byte  `json:"B,omitempty"`
+		N *string `json:"N,omitempty"`
+		S *string `json:"S,omitempty"`
+	}
+)
+
+// QueryAll runs the query following LastEvaluatedKey until every page has
+// been read, the items of all the pages are unmarshalled into bindModel.
+func (c Client) QueryAll(input *dynamoDBLib.QueryInput, bindModel interface{}) error {
+	return c.QueryAllWithContext(context.Background(), input, bindModel)
+}
+
+// QueryAllWithContext is QueryAll with a context that can cancel the
+// requests.
+func (c Client) QueryAllWithContext(ctx context.Context, input *dynamoDBLib.QueryInput, bindModel interface{}) error {
+	ctx, span := c.tracer.StartSpan(ctx, "dynamodb.QueryAll")
+	span.SetAttribute("table", aws.StringValue(input.TableName))
+
+	items := make([]map[string]*dynamoDBLib.AttributeValue, 0)
+	pages := 0
+
+	err := c.DynamoDBAPI.QueryPagesWithContext(ctx, input, func(output *dynamoDBLib.QueryOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		pages++
+
+		return true
+	})
+
+	if err == nil {
+		err = dynamodbattribute.UnmarshalListOfMaps(items, &bindModel)
+	}
+
+	span.SetAttribute("pages", pages)
+	awswrappers.EndSpan(span, err)
+
+	return err
+}
+
+// NewQueryIterator creates an iterator over the pages of the query, starting
+// from input.ExclusiveStartKey which can be set from a cursor with
+// DecodeCursor.
+func (c Client) NewQueryIterator(input *dynamoDBLib.QueryInput) *QueryIterator {
+	return c.NewQueryIteratorWithContext(context.Background(), input)
+}
+
+// NewQueryIteratorWithContext is NewQueryIterator with a context that can
+// cancel the requests.
+func (c Client) NewQueryIteratorWithContext(ctx context.Context, input *dynamoDBLib.QueryInput) *QueryIterator {
+	return &QueryIterator{
+		client: c,
+		ctx:    ctx,
+		input:  *input,
+	}
+}
+
+// Next queries the next page, returning false once the last page has been
+// read or the query fails, see Err.
+func (i *QueryIterator) Next() bool {
+	if i.err != nil {
+		return false
+	}
+
+	if i.output != nil {
+		if len(i.output.LastEvaluatedKey) == 0 {
+			return false
+		}
+
+		i.input.ExclusiveStartKey = i.output.LastEvaluatedKey
+	}
+
+	ctx, span := i.client.tracer.StartSpan(i.ctx, "dynamodb.QueryPage")
+	span.SetAttribute("table", aws.StringValue(i.input.TableName))
+
+	i.output, i.err = i.client.DynamoDBAPI.QueryWithContext(ctx, &i.input)
+	awswrappers.EndSpan(span, i.err)
+
+	return i.err == nil
+}
+
+// Page unmarshals the items of the current page into bindModel.
+func (i *QueryIterator) Page(bindModel interface{}) error {
+	if i.output == nil {
+		return errors.New("Next must be called before reading a page")
+	}
+
+	return dynamodbattribute.UnmarshalListOfMaps(i.output.Items, &bindModel)
+}
+
+// Output returns the output of the query for the current page.
+func (i *QueryIterator) Output() *dynamoDBLib.QueryOutput {
+	return i.output
+}
+
+// Cursor returns an opaque cursor to resume the query after the current
+// page, it is empty after the last page.
+func (i *QueryIterator) Cursor() (string, error) {
+	if i.output == nil {
+		return "", nil
+	}
+
+	return EncodeCursor(i.output.LastEvaluatedKey)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (i *QueryIterator) Err() error {
+	return i.err
+}
+
+// EncodeCursor encodes the LastEvaluatedKey of a query or scan as an opaque
+// URL safe string, an empty key gives an empty cursor.
+func EncodeCursor(lastEvaluatedKey map[string]*dynamoDBLib.AttributeValue) (string, error) {
+	if len(lastEvaluatedKey) == 0 {
+		return "", nil
+	}
+
+	values := make(map[string]cursorValue, len(lastEvaluatedKey))
+	for name, value := range lastEvaluatedKey {
+		if value == nil || !(cursorValue{value.B, value.N, value.S}).valid() {
+			return "", errors.Errorf("Unable to encode cursor, key attribute '%s' isn't a string, number or binary", name)
+		}
+
+		values[name] = cursorValue{value.B, value.N, value.S}
+	}
+
+	cursor, err := json.Marshal(values)
+	if err != nil {
+		return "", errors.Wrap(err, "Unable to encode cursor")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(cursor), nil
+}
+
+// DecodeCursor decodes a cursor created by EncodeCursor into the key to use
+// as the ExclusiveStartKey of a query or scan, an empty cursor gives a nil
+// key.
+func DecodeCursor(cursor string) (map[string]*dynamoDBLib.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to decode cursor")
+	}
+
+	var values map[string]cursorValue
+	err = json.Unmarshal(decoded, &values)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to decode cursor")
+	}
+
+	key := make(map[string]*dynamoDBLib.AttributeValue, len(values))
+	for name, value := range values {
+		if !value.valid() {
+			return nil, errors.Errorf("Unable to decode cursor, key attribute '%s' isn't a string, number or binary", name)
+		}
+
+		key[name] = &dynamoDBLib.AttributeValue{
+			B: value.B,
+			N: value.N,
+			S: value.S,
+		}
+	}
+
+	return key, nil
+}
+
+// valid reports whether exactly one of the string, number or binary values
+// is set, the only types a key attribute can have.
+func (v cursorValue) valid() bool {
+	set := 0
+	for _, isSet := range []bool{v.B != nil, v.N != nil, v.S != nil} {
+		if isSet {
+			set++
+		}
+	}
+
+	return set == 1
+}
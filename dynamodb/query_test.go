@@ -0,0 +1,193 @@
+package dynamodb_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	dynamoDBLib "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/dynamodb"
+)
+
+func TestQuery(t *testing.T) {
+	input := &dynamoDBLib.QueryInput{
+		KeyConditionExpression: aws.String("id = :id"),
+		TableName:              aws.String("test_table_name"),
+	}
+
+	t.Run(".QueryAll()", func(t *testing.T) {
+		t.Run("UnmarshalsEveryPage", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{mockQuery: pagedQuery(3)}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			var bindModel []TestBatchGetModel
+
+			err = testClient.QueryAll(input, &bindModel)
+			assert.NoError(t, err)
+
+			assert.Equal(t, []TestBatchGetModel{{"item_0"}, {"item_1"}, {"item_2"}}, bindModel)
+		})
+
+		t.Run("ReturnsQueryError", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{
+				mockQuery: func(input *dynamoDBLib.QueryInput) (*dynamoDBLib.QueryOutput, error) {
+					return nil, errors.New("Query error")
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			var bindModel []TestBatchGetModel
+
+			err = testClient.QueryAll(input, &bindModel)
+			assert.EqualError(t, err, "Query error")
+		})
+	})
+
+	t.Run(".NewQueryIterator()", func(t *testing.T) {
+		t.Run("IteratesOverPages", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{mockQuery: pagedQuery(3)}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			var ids []string
+			var cursors []string
+
+			iterator := testClient.NewQueryIterator(input)
+			for iterator.Next() {
+				var page []TestBatchGetModel
+
+				err = iterator.Page(&page)
+				assert.NoError(t, err)
+
+				cursor, err := iterator.Cursor()
+				assert.NoError(t, err)
+
+				ids = append(ids, page[0].ID)
+				cursors = append(cursors, cursor)
+			}
+
+			assert.NoError(t, iterator.Err())
+			assert.Equal(t, []string{"item_0", "item_1", "item_2"}, ids)
+			assert.NotEmpty(t, cursors[0])
+			assert.Empty(t, cursors[2])
+		})
+
+		t.Run("ResumesFromCursor", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{mockQuery: pagedQuery(3)}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			iterator := testClient.NewQueryIterator(input)
+			iterator.Next()
+
+			cursor, err := iterator.Cursor()
+			assert.NoError(t, err)
+
+			startKey, err := dynamodb.DecodeCursor(cursor)
+			assert.NoError(t, err)
+
+			resumedInput := *input
+			resumedInput.ExclusiveStartKey = startKey
+
+			resumed := testClient.NewQueryIterator(&resumedInput)
+
+			var page []TestBatchGetModel
+			assert.True(t, resumed.Next())
+			assert.NoError(t, resumed.Page(&page))
+			assert.Equal(t, []TestBatchGetModel{{"item_1"}}, page)
+		})
+
+		t.Run("StopsOnError", func(t *testing.T) {
+			mockSDKClient := &MockSDKClient{
+				mockQuery: func(input *dynamoDBLib.QueryInput) (*dynamoDBLib.QueryOutput, error) {
+					return nil, errors.New("Query error")
+				},
+			}
+
+			testClient, err := NewTestClient(mockSDKClient)
+			assert.Nil(t, err)
+
+			iterator := testClient.NewQueryIterator(input)
+
+			assert.False(t, iterator.Next())
+			assert.EqualError(t, iterator.Err(), "Query error")
+		})
+	})
+
+	t.Run(".EncodeCursor()", func(t *testing.T) {
+		t.Run("RoundTripsKeyTypes", func(t *testing.T) {
+			key := map[string]*dynamoDBLib.AttributeValue{
+				"id":         {S: aws.String("foo")},
+				"created_at": {N: aws.String("10")},
+				"hash":       {B: []byte("bar")},
+			}
+
+			cursor, err := dynamodb.EncodeCursor(key)
+			assert.NoError(t, err)
+
+			decoded, err := dynamodb.DecodeCursor(cursor)
+			assert.NoError(t, err)
+			assert.Equal(t, key, decoded)
+		})
+
+		t.Run("ReturnsErrorForNonKeyType", func(t *testing.T) {
+			_, err := dynamodb.EncodeCursor(map[string]*dynamoDBLib.AttributeValue{
+				"sent": {BOOL: aws.Bool(true)},
+			})
+
+			assert.EqualError(t, err, "Unable to encode cursor, key attribute 'sent' isn't a string, number or binary")
+		})
+	})
+
+	t.Run(".DecodeCursor()", func(t *testing.T) {
+		t.Run("ReturnsErrorForInvalidCursor", func(t *testing.T) {
+			_, err := dynamodb.DecodeCursor("not a cursor")
+			assert.Error(t, err)
+		})
+
+		t.Run("ReturnsErrorForInvalidKeyAttribute", func(t *testing.T) {
+			for _, value := range []string{`{}`, `{"N":"1","S":"foo"}`} {
+				cursor := base64.RawURLEncoding.EncodeToString([]byte(`{"id":` + value + `}`))
+				_, err := dynamodb.DecodeCursor(cursor)
+
+				assert.EqualError(t, err, "Unable to decode cursor, key attribute 'id' isn't a string, number or binary")
+			}
+		})
+	})
+}
+
+// pagedQuery returns a query with a single item on each of the given number
+// of pages, the page is chosen by the ExclusiveStartKey.
+func pagedQuery(pages int) func(*dynamoDBLib.QueryInput) (*dynamoDBLib.QueryOutput, error) {
+	return func(input *dynamoDBLib.QueryInput) (*dynamoDBLib.QueryOutput, error) {
+		page := 0
+		if input.ExclusiveStartKey != nil {
+			fmt.Sscanf(*input.ExclusiveStartKey["id"].S, "item_%d", &page)
+			page++
+		}
+
+		id := fmt.Sprintf("item_%d", page)
+		output := &dynamoDBLib.QueryOutput{
+			Items: []map[string]*dynamoDBLib.AttributeValue{
+				{"id": {S: aws.String(id)}},
+			},
+		}
+
+		if page < pages-1 {
+			output.LastEvaluatedKey = map[string]*dynamoDBLib.AttributeValue{
+				"id": {S: aws.String(id)},
+			}
+		}
+
+		return output, nil
+	}
+}
@@ -1,12 +1,14 @@
 package elastictranscoder
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	elastictranscoderLib "github.com/aws/aws-sdk-go/service/elastictranscoder"
 	"github.com/aws/aws-sdk-go/service/elastictranscoder/elastictranscoderiface"
+	"github.com/vidsy/awswrappers"
 )
 
 type (
@@ -14,34 +16,42 @@ type (
 	Client struct {
 		elastictranscoderiface.ElasticTranscoderAPI
 		clientConfig *ClientConfig
+		tracer       awswrappers.Tracer
 	}
 )
 
-// NewClient creates a new wrapper based on the environment.
+// NewClient creates a new wrapper based on the environment, see
+// NewClientWithOptions for more settings.
 func NewClient(config *ClientConfig, useDevelopmentClient bool, client elastictranscoderiface.ElasticTranscoderAPI) *Client {
-	if client == nil {
-		var elastictranscoderClient *elastictranscoderLib.ElasticTranscoder
-
-		if useDevelopmentClient {
-			elastictranscoderClient = elastictranscoderLib.New(session.New(), aws.NewConfig().WithEndpoint(config.Endpoint))
-		} else {
-			elastictranscoderClient = elastictranscoderLib.New(session.New())
-		}
-
-		return &Client{
-			elastictranscoderClient,
-			config,
-		}
-	}
+	return NewClientWithOptions(
+		WithAPI(client),
+		WithClientConfig(config),
+		WithDevelopmentMode(useDevelopmentClient),
+	)
+}
 
-	return &Client{
-		client,
-		config,
-	}
+// NewClientWithSession creates a new wrapper using the given session, see
+// awswrappers.NewSession.
+func NewClientWithSession(config *ClientConfig, useDevelopmentClient bool, sess *session.Session) *Client {
+	return NewClientWithOptions(
+		WithClientConfig(config),
+		WithDevelopmentMode(useDevelopmentClient),
+		WithSession(sess),
+	)
 }
 
 // CreateNewJob creates a new elastictranscoder job.
 func (c Client) CreateNewJob(pipelineID string, inputKey string, outputKey string, outputPresetID string, outputKeyPrefix string, thumbnailPattern string, metadata map[string]*string) (string, error) {
+	return c.CreateNewJobWithContext(context.Background(), pipelineID, inputKey, outputKey, outputPresetID, outputKeyPrefix, thumbnailPattern, metadata)
+}
+
+// CreateNewJobWithContext creates a new elastictranscoder job, the context
+// can cancel the request.
+func (c Client) CreateNewJobWithContext(ctx context.Context, pipelineID string, inputKey string, outputKey string, outputPresetID string, outputKeyPrefix string, thumbnailPattern string, metadata map[string]*string) (string, error) {
+	ctx, span := c.tracer.StartSpan(ctx, "elastictranscoder.CreateNewJob")
+	span.SetAttribute("pipeline", pipelineID)
+	span.SetAttribute("key", inputKey)
+
 	params := &elastictranscoderLib.CreateJobInput{
 		PipelineId: aws.String(pipelineID),
 		Input: &elastictranscoderLib.JobInput{
@@ -59,17 +69,21 @@ func (c Client) CreateNewJob(pipelineID string, inputKey string, outputKey strin
 		params.Output.ThumbnailPattern = aws.String(thumbnailPattern)
 	}
 
-	response, err := c.CreateJob(params)
-	if err != nil {
-		return "", err
-	}
-
-	if response.Job == nil {
-		return "", fmt.Errorf(
+	response, err := c.CreateJobWithContext(ctx, params)
+	if err == nil && response.Job == nil {
+		err = fmt.Errorf(
 			"No Job returned from c.CreateJob client method: %v",
 			response,
 		)
 	}
 
+	if err != nil {
+		awswrappers.EndSpan(span, err)
+		return "", err
+	}
+
+	span.SetAttribute("job_id", *response.Job.Id)
+	span.End()
+
 	return *response.Job.Id, nil
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	elastictranscoderLib "github.com/aws/aws-sdk-go/service/elastictranscoder"
 	"github.com/aws/aws-sdk-go/service/elastictranscoder/elastictranscoderiface"
 	"github.com/vidsy/awswrappers/elastictranscoder"
@@ -13,11 +14,16 @@ type (
 	MockSDKClient struct {
 		elastictranscoderiface.ElasticTranscoderAPI
 
-		mockCreateJob func(input *elastictranscoderLib.CreateJobInput) (*elastictranscoderLib.CreateJobResponse, error)
+		mockCreateJob     func(input *elastictranscoderLib.CreateJobInput) (*elastictranscoderLib.CreateJobResponse, error)
+		mockListPipelines func(input *elastictranscoderLib.ListPipelinesInput) (*elastictranscoderLib.ListPipelinesOutput, error)
 	}
 )
 
-func (m MockSDKClient) CreateJob(input *elastictranscoderLib.CreateJobInput) (*elastictranscoderLib.CreateJobResponse, error) {
+func (m MockSDKClient) CreateJobWithContext(ctx aws.Context, input *elastictranscoderLib.CreateJobInput, opts ...request.Option) (*elastictranscoderLib.CreateJobResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	if m.mockCreateJob != nil {
 		return m.mockCreateJob(input)
 	}
@@ -25,6 +31,18 @@ func (m MockSDKClient) CreateJob(input *elastictranscoderLib.CreateJobInput) (*e
 	return nil, nil
 }
 
+func (m MockSDKClient) ListPipelinesWithContext(ctx aws.Context, input *elastictranscoderLib.ListPipelinesInput, opts ...request.Option) (*elastictranscoderLib.ListPipelinesOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.mockListPipelines != nil {
+		return m.mockListPipelines(input)
+	}
+
+	return &elastictranscoderLib.ListPipelinesOutput{}, nil
+}
+
 func NewTestClient(mockClient *MockSDKClient) *elastictranscoder.Client {
 	if mockClient == nil {
 		mockClient = &MockSDKClient{}
@@ -0,0 +1,19 @@
+package elastictranscoder
+
+import (
+	"context"
+
+	elastictranscoderLib "github.com/aws/aws-sdk-go/service/elastictranscoder"
+	"github.com/pkg/errors"
+)
+
+// HealthCheck checks Elastic Transcoder can be reached by listing
+// pipelines.
+func (c Client) HealthCheck(ctx context.Context) error {
+	_, err := c.ElasticTranscoderAPI.ListPipelinesWithContext(ctx, &elastictranscoderLib.ListPipelinesInput{})
+	if err != nil {
+		return errors.Wrap(err, "Unable to list Elastic Transcoder pipelines")
+	}
+
+	return nil
+}
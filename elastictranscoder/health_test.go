@@ -0,0 +1,47 @@
+package elastictranscoder_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	elastictranscoderLib "github.com/aws/aws-sdk-go/service/elastictranscoder"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/elastictranscoder"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run(".HealthCheck()", func(t *testing.T) {
+		var cases = []struct {
+			name          string
+			err           error
+			expectedError string
+		}{
+			{"PassesWhenReachable", nil, ""},
+			{"ReturnsErrorWhenUnreachable", errors.New("Unavailable"), "Unable to list Elastic Transcoder pipelines: Unavailable"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				called := false
+
+				mockClient := &MockSDKClient{
+					mockListPipelines: func(input *elastictranscoderLib.ListPipelinesInput) (*elastictranscoderLib.ListPipelinesOutput, error) {
+						called = true
+						return &elastictranscoderLib.ListPipelinesOutput{}, c.err
+					},
+				}
+
+				client := elastictranscoder.NewClientWithOptions(elastictranscoder.WithAPI(mockClient))
+				err := client.HealthCheck(context.Background())
+
+				assert.True(t, called)
+				if c.expectedError == "" {
+					assert.NoError(t, err)
+				} else {
+					assert.EqualError(t, err, c.expectedError)
+				}
+			})
+		}
+	})
+}
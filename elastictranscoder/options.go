@@ -0,0 +1,139 @@
+package elastictranscoder
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	elastictranscoderLib "github.com/aws/aws-sdk-go/service/elastictranscoder"
+	"github.com/aws/aws-sdk-go/service/elastictranscoder/elastictranscoderiface"
+	"github.com/vidsy/awswrappers"
+)
+
+type (
+	// Option configures a Client created by NewClientWithOptions.
+	Option func(*options)
+
+	options struct {
+		api             elastictranscoderiface.ElasticTranscoderAPI
+		config          *ClientConfig
+		developmentMode bool
+		endpoint        string
+		logger          awswrappers.Logger
+		metrics         awswrappers.Metrics
+		session         *session.Session
+		tracer          awswrappers.Tracer
+	}
+)
+
+// WithAPI uses the given Elastic Transcoder API instead of creating one, the session and
+// endpoint options are ignored.
+func WithAPI(api elastictranscoderiface.ElasticTranscoderAPI) Option {
+	return func(o *options) {
+		o.api = api
+	}
+}
+
+// WithClientConfig sets the config of the Client.
+func WithClientConfig(config *ClientConfig) Option {
+	return func(o *options) {
+		o.config = config
+	}
+}
+
+// WithDevelopmentMode sends requests to ClientConfig.Endpoint.
+func WithDevelopmentMode(developmentMode bool) Option {
+	return func(o *options) {
+		o.developmentMode = developmentMode
+	}
+}
+
+// WithEndpoint sends requests to the given endpoint, taking precedence over
+// ClientConfig.Endpoint in development mode.
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithLogger logs the requests made by the Client and its retries to the
+// given logger, it has no effect when the API is given with WithAPI.
+func WithLogger(logger awswrappers.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithMetrics records the requests made by the Client to the given
+// metrics, it has no effect when the API is given with WithAPI.
+func WithMetrics(metrics awswrappers.Metrics) Option {
+	return func(o *options) {
+		o.metrics = metrics
+	}
+}
+
+// WithSession creates the Elastic Transcoder API from the given session, see
+// awswrappers.NewSession.
+func WithSession(sess *session.Session) Option {
+	return func(o *options) {
+		o.session = sess
+	}
+}
+
+// WithTracer starts a span for each call made with the Client, see
+// awswrappers.Tracer.
+func WithTracer(tracer awswrappers.Tracer) Option {
+	return func(o *options) {
+		o.tracer = tracer
+	}
+}
+
+// NewClientWithOptions creates a new wrapper from the given options, by
+// default an empty ClientConfig and a new default session are used.
+func NewClientWithOptions(opts ...Option) *Client {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.config == nil {
+		o.config = &ClientConfig{}
+	}
+
+	tracer := o.tracer
+	if tracer == nil {
+		tracer = awswrappers.NopTracer{}
+	}
+
+	if o.api == nil {
+		if o.session == nil {
+			o.session = session.New()
+		}
+
+		endpoint := o.endpoint
+		if endpoint == "" && o.developmentMode {
+			endpoint = o.config.Endpoint
+		}
+
+		var elastictranscoderClient *elastictranscoderLib.ElasticTranscoder
+
+		if endpoint != "" {
+			elastictranscoderClient = elastictranscoderLib.New(o.session, awswrappers.DevelopmentConfig(endpoint))
+		} else {
+			elastictranscoderClient = elastictranscoderLib.New(o.session)
+		}
+
+		if o.logger != nil {
+			awswrappers.LogRequests(&elastictranscoderClient.Handlers, o.logger)
+		}
+
+		if o.metrics != nil {
+			awswrappers.RecordRequests(&elastictranscoderClient.Handlers, o.metrics)
+		}
+
+		o.api = elastictranscoderClient
+	}
+
+	return &Client{
+		o.api,
+		o.config,
+		tracer,
+	}
+}
@@ -0,0 +1,143 @@
+package awswrappers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// HealthChecker checks that a dependency can be reached, returning an
+	// error describing why it can't.
+	HealthChecker interface {
+		HealthCheck(ctx context.Context) error
+	}
+
+	// HealthCheckerFunc adapts a function to a HealthChecker.
+	HealthCheckerFunc func(ctx context.Context) error
+
+	// HealthStatus is the outcome of checking a single dependency.
+	HealthStatus struct {
+		Name     string        `json:"name"`
+		Healthy  bool          `json:"healthy"`
+		Error    string        `json:"error,omitempty"`
+		Duration time.Duration `json:"duration"`
+	}
+
+	// HealthReport is the outcome of checking every dependency, Healthy is
+	// only true if every dependency is healthy.
+	HealthReport struct {
+		Healthy      bool           `json:"healthy"`
+		Dependencies []HealthStatus `json:"dependencies"`
+	}
+
+	// HealthCheck aggregates the HealthCheckers of the dependencies of a
+	// service, it is an http.Handler serving the HealthReport as JSON so it
+	// can be used as a readiness endpoint.
+	HealthCheck struct {
+		checkers map[string]HealthChecker
+		mutex    sync.RWMutex
+		timeout  time.Duration
+	}
+)
+
+// HealthCheck calls the function with the context.
+func (f HealthCheckerFunc) HealthCheck(ctx context.Context) error {
+	return f(ctx)
+}
+
+// NewHealthCheck creates a new HealthCheck with no dependencies, each
+// dependency is given the timeout to respond (0 means no timeout).
+func NewHealthCheck(timeout time.Duration) *HealthCheck {
+	return &HealthCheck{
+		checkers: make(map[string]HealthChecker),
+		timeout:  timeout,
+	}
+}
+
+// Register adds a dependency to be checked, replacing any dependency with
+// the same name.
+func (h *HealthCheck) Register(name string, checker HealthChecker) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.checkers[name] = checker
+}
+
+// Check checks every dependency concurrently, the report lists them by
+// name.
+func (h *HealthCheck) Check(ctx context.Context) HealthReport {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	report := HealthReport{
+		Healthy:      true,
+		Dependencies: make([]HealthStatus, 0, len(h.checkers)),
+	}
+
+	var reportMutex sync.Mutex
+	var checksWaitGroup sync.WaitGroup
+	checksWaitGroup.Add(len(h.checkers))
+
+	for name, checker := range h.checkers {
+		go func(name string, checker HealthChecker) {
+			defer checksWaitGroup.Done()
+
+			status := h.checkDependency(ctx, name, checker)
+
+			reportMutex.Lock()
+			defer reportMutex.Unlock()
+
+			report.Dependencies = append(report.Dependencies, status)
+			report.Healthy = report.Healthy && status.Healthy
+		}(name, checker)
+	}
+
+	checksWaitGroup.Wait()
+
+	sort.Slice(report.Dependencies, func(i, j int) bool {
+		return report.Dependencies[i].Name < report.Dependencies[j].Name
+	})
+
+	return report
+}
+
+// ServeHTTP responds with the HealthReport as JSON, with a 503 status code
+// when any dependency is unhealthy.
+func (h *HealthCheck) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := h.Check(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !report.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	json.NewEncoder(w).Encode(report)
+}
+
+func (h *HealthCheck) checkDependency(ctx context.Context, name string, checker HealthChecker) HealthStatus {
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := checker.HealthCheck(ctx)
+
+	status := HealthStatus{
+		Name:     name,
+		Healthy:  err == nil,
+		Duration: time.Since(start),
+	}
+
+	if err != nil {
+		status.Error = err.Error()
+	}
+
+	return status
+}
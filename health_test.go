@@ -0,0 +1,96 @@
+package awswrappers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers"
+)
+
+func TestHealthCheck(t *testing.T) {
+	t.Run(".Check()", func(t *testing.T) {
+		t.Run("ReportsEachDependencyByName", func(t *testing.T) {
+			healthCheck := awswrappers.NewHealthCheck(0)
+			healthCheck.Register("sqs", awswrappers.HealthCheckerFunc(func(ctx context.Context) error {
+				return nil
+			}))
+			healthCheck.Register("dynamodb", awswrappers.HealthCheckerFunc(func(ctx context.Context) error {
+				return errors.New("Unable to describe table")
+			}))
+
+			report := healthCheck.Check(context.Background())
+
+			assert.False(t, report.Healthy)
+			if assert.Len(t, report.Dependencies, 2) {
+				assert.Equal(t, "dynamodb", report.Dependencies[0].Name)
+				assert.False(t, report.Dependencies[0].Healthy)
+				assert.Equal(t, "Unable to describe table", report.Dependencies[0].Error)
+
+				assert.Equal(t, "sqs", report.Dependencies[1].Name)
+				assert.True(t, report.Dependencies[1].Healthy)
+				assert.Empty(t, report.Dependencies[1].Error)
+			}
+		})
+
+		t.Run("IsHealthyWithoutDependencies", func(t *testing.T) {
+			report := awswrappers.NewHealthCheck(0).Check(context.Background())
+
+			assert.True(t, report.Healthy)
+			assert.Empty(t, report.Dependencies)
+		})
+
+		t.Run("TimesOutSlowDependencies", func(t *testing.T) {
+			healthCheck := awswrappers.NewHealthCheck(10 * time.Millisecond)
+			healthCheck.Register("s3", awswrappers.HealthCheckerFunc(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}))
+
+			report := healthCheck.Check(context.Background())
+
+			assert.False(t, report.Healthy)
+			if assert.Len(t, report.Dependencies, 1) {
+				assert.Equal(t, context.DeadlineExceeded.Error(), report.Dependencies[0].Error)
+			}
+		})
+	})
+
+	t.Run(".ServeHTTP()", func(t *testing.T) {
+		t.Run("RespondsWithReport", func(t *testing.T) {
+			healthCheck := awswrappers.NewHealthCheck(0)
+			healthCheck.Register("sns", awswrappers.HealthCheckerFunc(func(ctx context.Context) error {
+				return nil
+			}))
+
+			recorder := httptest.NewRecorder()
+			healthCheck.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+			var report awswrappers.HealthReport
+			err := json.NewDecoder(recorder.Body).Decode(&report)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			assert.True(t, report.Healthy)
+			assert.Len(t, report.Dependencies, 1)
+		})
+
+		t.Run("RespondsWithServiceUnavailableWhenUnhealthy", func(t *testing.T) {
+			healthCheck := awswrappers.NewHealthCheck(0)
+			healthCheck.Register("kms", awswrappers.HealthCheckerFunc(func(ctx context.Context) error {
+				return errors.New("Unable to list KMS keys")
+			}))
+
+			recorder := httptest.NewRecorder()
+			healthCheck.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+			assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		})
+	})
+}
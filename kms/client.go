@@ -1,12 +1,14 @@
 package kms
 
 import (
+	"context"
 	"encoding/base64"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	kmsLib "github.com/aws/aws-sdk-go/service/kms"
 	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/vidsy/awswrappers"
 )
 
 type (
@@ -14,25 +16,57 @@ type (
 	Client struct {
 		kmsiface.KMSAPI
 		developmentMode bool
+		logger          awswrappers.Logger
+		tracer          awswrappers.Tracer
+		healthCheckKeys []string
 	}
 )
 
-// NewClient creates a new wrapper based on the environment.
+// NewClient creates a new wrapper based on the environment, see
+// NewClientWithOptions for more settings.
 func NewClient(developmentMode bool, client kmsiface.KMSAPI) *Client {
-	if client == nil {
-		client = kmsLib.New(session.New())
-	}
+	return NewClientWithOptions(
+		WithAPI(client),
+		WithDevelopmentMode(developmentMode),
+	)
+}
 
-	return &Client{
-		client,
-		developmentMode,
-	}
+// NewClientWithSession creates a new wrapper using the given session, see
+// awswrappers.NewSession.
+func NewClientWithSession(developmentMode bool, sess *session.Session) *Client {
+	return NewClientWithOptions(
+		WithDevelopmentMode(developmentMode),
+		WithSession(sess),
+	)
 }
 
 // EncryptData takes a KMS key arn and data to encrypt and
 // returns the encrypted Ciphertext Blob.
 func (c Client) EncryptData(keyID string, data []byte) (string, error) {
+	return c.EncryptDataWithContext(context.Background(), keyID, data)
+}
+
+// EncryptDataWithContext takes a KMS key arn and data to encrypt and
+// returns the encrypted Ciphertext Blob, the context can cancel the request.
+func (c Client) EncryptDataWithContext(ctx context.Context, keyID string, data []byte) (string, error) {
+	ctx, span := c.tracer.StartSpan(ctx, "kms.EncryptData")
+	span.SetAttribute("key_id", keyID)
+	span.SetAttribute("development_mode", c.developmentMode)
+
+	encryptedData, err := c.encryptData(ctx, keyID, data)
+	awswrappers.EndSpan(span, err)
+
+	return encryptedData, err
+}
+
+func (c Client) encryptData(ctx context.Context, keyID string, data []byte) (string, error) {
 	if c.developmentMode {
+		c.logger.Log("Base64 encoding data instead of encrypting in development mode", awswrappers.Fields{
+			"key_id":    keyID,
+			"operation": "EncryptData",
+			"service":   kmsLib.ServiceName,
+		})
+
 		return base64.StdEncoding.EncodeToString(
 			data,
 		), nil
@@ -43,7 +77,7 @@ func (c Client) EncryptData(keyID string, data []byte) (string, error) {
 		Plaintext: data,
 	}
 
-	result, err := c.Encrypt(input)
+	result, err := c.EncryptWithContext(ctx, input)
 	if err != nil {
 		return "", err
 	}
@@ -56,6 +90,22 @@ func (c Client) EncryptData(keyID string, data []byte) (string, error) {
 // DecryptData takes a blob of encrypted data and attempts to
 // decrypt it.
 func (c Client) DecryptData(data string) ([]byte, error) {
+	return c.DecryptDataWithContext(context.Background(), data)
+}
+
+// DecryptDataWithContext takes a blob of encrypted data and attempts to
+// decrypt it, the context can cancel the request.
+func (c Client) DecryptDataWithContext(ctx context.Context, data string) ([]byte, error) {
+	ctx, span := c.tracer.StartSpan(ctx, "kms.DecryptData")
+	span.SetAttribute("development_mode", c.developmentMode)
+
+	decryptedData, err := c.decryptData(ctx, data)
+	awswrappers.EndSpan(span, err)
+
+	return decryptedData, err
+}
+
+func (c Client) decryptData(ctx context.Context, data string) ([]byte, error) {
 	decodedData, err := base64.StdEncoding.DecodeString(
 		data,
 	)
@@ -64,6 +114,11 @@ func (c Client) DecryptData(data string) ([]byte, error) {
 	}
 
 	if c.developmentMode {
+		c.logger.Log("Base64 decoding data instead of decrypting in development mode", awswrappers.Fields{
+			"operation": "DecryptData",
+			"service":   kmsLib.ServiceName,
+		})
+
 		return decodedData, nil
 	}
 
@@ -71,7 +126,7 @@ func (c Client) DecryptData(data string) ([]byte, error) {
 		CiphertextBlob: decodedData,
 	}
 
-	result, err := c.Decrypt(input)
+	result, err := c.DecryptWithContext(ctx, input)
 	if err != nil {
 		return nil, err
 	}
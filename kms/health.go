@@ -0,0 +1,40 @@
+package kms
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	kmsLib "github.com/aws/aws-sdk-go/service/kms"
+	"github.com/pkg/errors"
+)
+
+// HealthCheck checks KMS can be reached, describing the keys set with
+// WithHealthCheckKeys or listing a key when none are set. It always passes
+// in development mode as KMS isn't used.
+func (c Client) HealthCheck(ctx context.Context) error {
+	if c.developmentMode {
+		return nil
+	}
+
+	if len(c.healthCheckKeys) == 0 {
+		_, err := c.KMSAPI.ListKeysWithContext(ctx, &kmsLib.ListKeysInput{
+			Limit: aws.Int64(1),
+		})
+		if err != nil {
+			return errors.Wrap(err, "Unable to list KMS keys")
+		}
+
+		return nil
+	}
+
+	for _, keyID := range c.healthCheckKeys {
+		_, err := c.KMSAPI.DescribeKeyWithContext(ctx, &kmsLib.DescribeKeyInput{
+			KeyId: aws.String(keyID),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Unable to describe KMS key '%s'", keyID)
+		}
+	}
+
+	return nil
+}
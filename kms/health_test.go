@@ -0,0 +1,109 @@
+package kms_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	kmsLib "github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/kms"
+)
+
+type (
+	MockKMSClient struct {
+		kmsiface.KMSAPI
+		mockDescribeKey func(*kmsLib.DescribeKeyInput) (*kmsLib.DescribeKeyOutput, error)
+		mockListKeys    func(*kmsLib.ListKeysInput) (*kmsLib.ListKeysOutput, error)
+	}
+)
+
+func (m MockKMSClient) DescribeKeyWithContext(ctx aws.Context, input *kmsLib.DescribeKeyInput, opts ...request.Option) (*kmsLib.DescribeKeyOutput, error) {
+	if m.mockDescribeKey != nil {
+		return m.mockDescribeKey(input)
+	}
+
+	return &kmsLib.DescribeKeyOutput{}, nil
+}
+
+func (m MockKMSClient) ListKeysWithContext(ctx aws.Context, input *kmsLib.ListKeysInput, opts ...request.Option) (*kmsLib.ListKeysOutput, error) {
+	if m.mockListKeys != nil {
+		return m.mockListKeys(input)
+	}
+
+	return &kmsLib.ListKeysOutput{}, nil
+}
+
+func TestHealth(t *testing.T) {
+	t.Run(".HealthCheck()", func(t *testing.T) {
+		t.Run("ListsKeysWithoutHealthCheckKeys", func(t *testing.T) {
+			listed := false
+
+			mockClient := &MockKMSClient{
+				mockListKeys: func(input *kmsLib.ListKeysInput) (*kmsLib.ListKeysOutput, error) {
+					listed = true
+					return &kmsLib.ListKeysOutput{}, nil
+				},
+			}
+
+			client := kms.NewClientWithOptions(kms.WithAPI(mockClient))
+			err := client.HealthCheck(context.Background())
+
+			assert.NoError(t, err)
+			assert.True(t, listed)
+		})
+
+		t.Run("ReturnsErrorWhenListingKeysFails", func(t *testing.T) {
+			mockClient := &MockKMSClient{
+				mockListKeys: func(input *kmsLib.ListKeysInput) (*kmsLib.ListKeysOutput, error) {
+					return nil, errors.New("Access denied")
+				},
+			}
+
+			client := kms.NewClientWithOptions(kms.WithAPI(mockClient))
+			err := client.HealthCheck(context.Background())
+
+			assert.EqualError(t, err, "Unable to list KMS keys: Access denied")
+		})
+
+		t.Run("ReturnsErrorForMissingKey", func(t *testing.T) {
+			mockClient := &MockKMSClient{
+				mockDescribeKey: func(input *kmsLib.DescribeKeyInput) (*kmsLib.DescribeKeyOutput, error) {
+					if *input.KeyId == "bar" {
+						return nil, errors.New("Not found")
+					}
+
+					return &kmsLib.DescribeKeyOutput{}, nil
+				},
+			}
+
+			client := kms.NewClientWithOptions(
+				kms.WithAPI(mockClient),
+				kms.WithHealthCheckKeys("foo", "bar"),
+			)
+			err := client.HealthCheck(context.Background())
+
+			assert.EqualError(t, err, "Unable to describe KMS key 'bar': Not found")
+		})
+
+		t.Run("PassesInDevelopmentMode", func(t *testing.T) {
+			mockClient := &MockKMSClient{
+				mockListKeys: func(input *kmsLib.ListKeysInput) (*kmsLib.ListKeysOutput, error) {
+					t.Fatal("Expected KMS not to be called in development mode")
+					return nil, nil
+				},
+			}
+
+			client := kms.NewClientWithOptions(
+				kms.WithAPI(mockClient),
+				kms.WithDevelopmentMode(true),
+			)
+			err := client.HealthCheck(context.Background())
+
+			assert.NoError(t, err)
+		})
+	})
+}
@@ -0,0 +1,137 @@
+package kms
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	kmsLib "github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/vidsy/awswrappers"
+)
+
+type (
+	// Option configures a Client created by NewClientWithOptions.
+	Option func(*options)
+
+	options struct {
+		api             kmsiface.KMSAPI
+		developmentMode bool
+		endpoint        string
+		healthCheckKeys []string
+		logger          awswrappers.Logger
+		metrics         awswrappers.Metrics
+		session         *session.Session
+		tracer          awswrappers.Tracer
+	}
+)
+
+// WithAPI uses the given KMS API instead of creating one, the session and
+// endpoint options are ignored.
+func WithAPI(api kmsiface.KMSAPI) Option {
+	return func(o *options) {
+		o.api = api
+	}
+}
+
+// WithDevelopmentMode base64 encodes and decodes data instead of calling
+// KMS.
+func WithDevelopmentMode(developmentMode bool) Option {
+	return func(o *options) {
+		o.developmentMode = developmentMode
+	}
+}
+
+// WithEndpoint sends requests to the given endpoint.
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithHealthCheckKeys sets the keys described by Client.HealthCheck.
+func WithHealthCheckKeys(names ...string) Option {
+	return func(o *options) {
+		o.healthCheckKeys = names
+	}
+}
+
+// WithLogger logs the requests made by the Client and its retries to the
+// given logger, it has no effect when the API is given with WithAPI.
+func WithLogger(logger awswrappers.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithMetrics records the requests made by the Client to the given
+// metrics, it has no effect when the API is given with WithAPI.
+func WithMetrics(metrics awswrappers.Metrics) Option {
+	return func(o *options) {
+		o.metrics = metrics
+	}
+}
+
+// WithSession creates the KMS API from the given session, see
+// awswrappers.NewSession.
+func WithSession(sess *session.Session) Option {
+	return func(o *options) {
+		o.session = sess
+	}
+}
+
+// WithTracer starts a span for each call made with the Client, see
+// awswrappers.Tracer.
+func WithTracer(tracer awswrappers.Tracer) Option {
+	return func(o *options) {
+		o.tracer = tracer
+	}
+}
+
+// NewClientWithOptions creates a new wrapper from the given options, by
+// default a new default session is used.
+func NewClientWithOptions(opts ...Option) *Client {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	logger := o.logger
+	if logger == nil {
+		logger = awswrappers.NopLogger{}
+	}
+
+	tracer := o.tracer
+	if tracer == nil {
+		tracer = awswrappers.NopTracer{}
+	}
+
+	if o.api == nil {
+		if o.session == nil {
+			o.session = session.New()
+		}
+
+		var kmsClient *kmsLib.KMS
+
+		if o.endpoint != "" {
+			kmsClient = kmsLib.New(o.session, awswrappers.DevelopmentConfig(o.endpoint))
+		} else {
+			kmsClient = kmsLib.New(o.session)
+		}
+
+		if o.logger != nil {
+			awswrappers.LogRequests(&kmsClient.Handlers, o.logger)
+		}
+
+		if o.metrics != nil {
+			awswrappers.RecordRequests(&kmsClient.Handlers, o.metrics)
+		}
+
+		o.api = kmsClient
+	}
+
+	return &Client{
+		o.api,
+		o.developmentMode,
+		logger,
+		tracer,
+		o.healthCheckKeys,
+	}
+}
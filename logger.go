@@ -0,0 +1,121 @@
+package awswrappers
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+type (
+	// Fields holds the structured values logged with a message.
+	Fields map[string]interface{}
+
+	// Logger receives the messages logged by the clients, implementations
+	// must be safe for concurrent use.
+	Logger interface {
+		Log(message string, fields Fields)
+	}
+
+	// LoggerFunc adapts a function to a Logger.
+	LoggerFunc func(message string, fields Fields)
+
+	// NopLogger discards every message.
+	NopLogger struct{}
+)
+
+var (
+	// requestResourceFields maps the input fields that identify the resource
+	// of a request to the name they are logged with.
+	requestResourceFields = map[string]string{
+		"Bucket":     "bucket",
+		"Key":        "key",
+		"KeyId":      "key_id",
+		"PipelineId": "pipeline",
+		"QueueUrl":   "queue",
+		"TableName":  "table",
+		"TopicArn":   "topic",
+	}
+)
+
+// Log calls the function with the message and fields.
+func (f LoggerFunc) Log(message string, fields Fields) {
+	f(message, fields)
+}
+
+// Log discards the message.
+func (NopLogger) Log(message string, fields Fields) {}
+
+// LogRequests adds handlers that log every completed request and every
+// retry, including retries of throttled requests, made with the handlers.
+// The logger can't be nil.
+func LogRequests(handlers *request.Handlers, logger Logger) {
+	handlers.AfterRetry.PushFront(func(r *request.Request) {
+		if r.Error == nil || r.RetryCount >= r.MaxRetries() {
+			return
+		}
+
+		if r.Retryable != nil && !*r.Retryable || r.Retryable == nil && !r.ShouldRetry(r) {
+			return
+		}
+
+		message := "Retrying AWS request"
+		if r.IsErrorThrottle() {
+			message = "Retrying throttled AWS request"
+		}
+
+		logger.Log(message, RequestFields(r))
+	})
+
+	handlers.Complete.PushBack(func(r *request.Request) {
+		message := "AWS request completed"
+		if r.Error != nil {
+			message = "AWS request failed"
+		}
+
+		logger.Log(message, RequestFields(r))
+	})
+}
+
+// RequestFields returns the fields logged for a request: the service,
+// operation, duration, retry count, any error and the resource the request
+// was made against.
+func RequestFields(r *request.Request) Fields {
+	fields := Fields{
+		"duration":  time.Since(r.Time),
+		"operation": r.Operation.Name,
+		"retries":   r.RetryCount,
+		"service":   r.ClientInfo.ServiceName,
+	}
+
+	if r.HTTPResponse != nil {
+		fields["status_code"] = r.HTTPResponse.StatusCode
+	}
+
+	if r.Error != nil {
+		fields["error"] = r.Error.Error()
+
+		if awsErr, ok := r.Error.(awserr.Error); ok {
+			fields["error_code"] = awsErr.Code()
+		}
+	}
+
+	params := reflect.Indirect(reflect.ValueOf(r.Params))
+	if params.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for fieldName, name := range requestResourceFields {
+		value := params.FieldByName(fieldName)
+		if !value.IsValid() || value.Kind() != reflect.Ptr || value.IsNil() {
+			continue
+		}
+
+		if s, ok := value.Elem().Interface().(string); ok {
+			fields[name] = s
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,85 @@
+package awswrappers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers"
+)
+
+const (
+	getQueueURLResponse = `<GetQueueUrlResponse><GetQueueUrlResult><QueueUrl>http://sqs/foo</QueueUrl></GetQueueUrlResult></GetQueueUrlResponse>`
+)
+
+func TestLogger(t *testing.T) {
+	t.Run(".LogRequests()", func(t *testing.T) {
+		t.Run("LogsRetriesAndCompletedRequests", func(t *testing.T) {
+			requestCount := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestCount++
+
+				if requestCount == 1 {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				w.Write([]byte(getQueueURLResponse))
+			}))
+			defer server.Close()
+
+			sess, err := awswrappers.NewSession(&awswrappers.SessionConfig{
+				AccessKeyID:     "access-key-id",
+				MaxRetries:      1,
+				Region:          "eu-west-1",
+				SecretAccessKey: "secret-access-key",
+			})
+			assert.NoError(t, err)
+
+			var mutex sync.Mutex
+			var messages []string
+			var fields []awswrappers.Fields
+
+			client := sqsLib.New(sess, awswrappers.DevelopmentConfig(server.URL))
+			awswrappers.LogRequests(&client.Handlers, awswrappers.LoggerFunc(func(message string, messageFields awswrappers.Fields) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				messages = append(messages, message)
+				fields = append(fields, messageFields)
+			}))
+
+			_, err = client.GetQueueUrl(&sqsLib.GetQueueUrlInput{
+				QueueName: aws.String("foo"),
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"Retrying AWS request", "AWS request completed"}, messages)
+			assert.Equal(t, "sqs", fields[1]["service"])
+			assert.Equal(t, "GetQueueUrl", fields[1]["operation"])
+			assert.Equal(t, 1, fields[1]["retries"])
+			assert.Equal(t, http.StatusOK, fields[1]["status_code"])
+		})
+	})
+
+	t.Run(".RequestFields()", func(t *testing.T) {
+		t.Run("IncludesResource", func(t *testing.T) {
+			sess, err := awswrappers.NewSession(&awswrappers.SessionConfig{Region: "eu-west-1"})
+			assert.NoError(t, err)
+
+			request, _ := sqsLib.New(sess).DeleteMessageRequest(&sqsLib.DeleteMessageInput{
+				QueueUrl:      aws.String("http://sqs/foo"),
+				ReceiptHandle: aws.String("handle"),
+			})
+
+			fields := awswrappers.RequestFields(request)
+
+			assert.Equal(t, "http://sqs/foo", fields["queue"])
+			assert.Equal(t, "DeleteMessage", fields["operation"])
+		})
+	})
+}
@@ -0,0 +1,148 @@
+package awswrappers
+
+import (
+	"expvar"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+type (
+	// RequestMetric describes a completed AWS request, Retries is the
+	// number of retries before it completed and ErrorCode is empty when it
+	// succeeded.
+	RequestMetric struct {
+		Duration   time.Duration
+		ErrorCode  string
+		Failed     bool
+		Operation  string
+		Retries    int
+		Service    string
+		StatusCode int
+		Throttled  bool
+	}
+
+	// Metrics records the requests made by the clients, implementations
+	// must be safe for concurrent use.
+	Metrics interface {
+		RecordRequest(metric RequestMetric)
+	}
+
+	// InMemoryMetrics keeps every recorded request, it is intended for
+	// tests.
+	InMemoryMetrics struct {
+		mutex    sync.Mutex
+		requests []RequestMetric
+	}
+
+	// ExpvarMetrics publishes per operation counters as an expvar.Map keyed
+	// by "service.operation", each holding the requests, errors, throttles,
+	// retries and total duration in nanoseconds.
+	ExpvarMetrics struct {
+		mutex      sync.Mutex
+		operations *expvar.Map
+	}
+)
+
+// RecordRequests adds a handler that records every completed request made
+// with the handlers, metrics can't be nil.
+func RecordRequests(handlers *request.Handlers, metrics Metrics) {
+	handlers.Complete.PushBack(func(r *request.Request) {
+		metrics.RecordRequest(NewRequestMetric(r))
+	})
+}
+
+// NewRequestMetric creates a RequestMetric from a completed request.
+func NewRequestMetric(r *request.Request) RequestMetric {
+	metric := RequestMetric{
+		Duration:  time.Since(r.Time),
+		Failed:    r.Error != nil,
+		Operation: r.Operation.Name,
+		Retries:   r.RetryCount,
+		Service:   r.ClientInfo.ServiceName,
+		Throttled: r.Error != nil && r.IsErrorThrottle(),
+	}
+
+	if r.HTTPResponse != nil {
+		metric.StatusCode = r.HTTPResponse.StatusCode
+	}
+
+	if awsErr, ok := r.Error.(awserr.Error); ok {
+		metric.ErrorCode = awsErr.Code()
+	}
+
+	return metric
+}
+
+// NewInMemoryMetrics creates a new InMemoryMetrics with no requests.
+func NewInMemoryMetrics() *InMemoryMetrics {
+	return &InMemoryMetrics{}
+}
+
+// RecordRequest keeps the metric.
+func (m *InMemoryMetrics) RecordRequest(metric RequestMetric) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.requests = append(m.requests, metric)
+}
+
+// Requests returns the metrics recorded so far in the order they were
+// recorded.
+func (m *InMemoryMetrics) Requests() []RequestMetric {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	requests := make([]RequestMetric, len(m.requests))
+	copy(requests, m.requests)
+
+	return requests
+}
+
+// NewExpvarMetrics creates a new ExpvarMetrics published under the given
+// name, like expvar.Publish it panics if the name is already in use.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	return &ExpvarMetrics{
+		operations: expvar.NewMap(name),
+	}
+}
+
+// RecordRequest adds the metric to the counters of its operation.
+func (m *ExpvarMetrics) RecordRequest(metric RequestMetric) {
+	counters := m.operationCounters(metric.Service + "." + metric.Operation)
+
+	counters.Add("requests", 1)
+	counters.Add("retries", int64(metric.Retries))
+	counters.Add("duration_ns", int64(metric.Duration))
+
+	if metric.Failed {
+		counters.Add("errors", 1)
+	}
+
+	if metric.Throttled {
+		counters.Add("throttles", 1)
+	}
+}
+
+// Operation returns the counters of the given "service.operation", or nil
+// if no request has been recorded for it.
+func (m *ExpvarMetrics) Operation(name string) *expvar.Map {
+	counters, _ := m.operations.Get(name).(*expvar.Map)
+	return counters
+}
+
+func (m *ExpvarMetrics) operationCounters(name string) *expvar.Map {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if counters, ok := m.operations.Get(name).(*expvar.Map); ok {
+		return counters
+	}
+
+	counters := new(expvar.Map).Init()
+	m.operations.Set(name, counters)
+
+	return counters
+}
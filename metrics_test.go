@@ -0,0 +1,91 @@
+package awswrappers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers"
+)
+
+func newMetricsTestClient(t *testing.T, statusCode int, metrics awswrappers.Metrics) (*sqsLib.SQS, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		if statusCode == http.StatusOK {
+			w.Write([]byte(getQueueURLResponse))
+		}
+	}))
+
+	sess, err := awswrappers.NewSession(&awswrappers.SessionConfig{
+		AccessKeyID:     "access-key-id",
+		MaxRetries:      1,
+		Region:          "eu-west-1",
+		SecretAccessKey: "secret-access-key",
+	})
+	assert.NoError(t, err)
+
+	client := sqsLib.New(sess, awswrappers.DevelopmentConfig(server.URL))
+	awswrappers.RecordRequests(&client.Handlers, metrics)
+
+	return client, server.Close
+}
+
+func TestMetrics(t *testing.T) {
+	t.Run(".RecordRequests()", func(t *testing.T) {
+		t.Run("RecordsSuccessfulRequest", func(t *testing.T) {
+			metrics := awswrappers.NewInMemoryMetrics()
+			client, closeServer := newMetricsTestClient(t, http.StatusOK, metrics)
+			defer closeServer()
+
+			_, err := client.GetQueueUrl(&sqsLib.GetQueueUrlInput{QueueName: aws.String("foo")})
+			assert.NoError(t, err)
+
+			requests := metrics.Requests()
+			if assert.Len(t, requests, 1) {
+				assert.Equal(t, "sqs", requests[0].Service)
+				assert.Equal(t, "GetQueueUrl", requests[0].Operation)
+				assert.Equal(t, http.StatusOK, requests[0].StatusCode)
+				assert.Equal(t, 0, requests[0].Retries)
+				assert.False(t, requests[0].Failed)
+				assert.True(t, requests[0].Duration > 0)
+			}
+		})
+
+		t.Run("RecordsFailedRequestWithRetries", func(t *testing.T) {
+			metrics := awswrappers.NewInMemoryMetrics()
+			client, closeServer := newMetricsTestClient(t, http.StatusServiceUnavailable, metrics)
+			defer closeServer()
+
+			_, err := client.GetQueueUrl(&sqsLib.GetQueueUrlInput{QueueName: aws.String("foo")})
+			assert.Error(t, err)
+
+			requests := metrics.Requests()
+			if assert.Len(t, requests, 1) {
+				assert.Equal(t, http.StatusServiceUnavailable, requests[0].StatusCode)
+				assert.Equal(t, 1, requests[0].Retries)
+				assert.True(t, requests[0].Failed)
+			}
+		})
+	})
+
+	t.Run("ExpvarMetrics", func(t *testing.T) {
+		t.Run("CountsRequestsPerOperation", func(t *testing.T) {
+			metrics := awswrappers.NewExpvarMetrics("awswrappers_test_requests")
+
+			metrics.RecordRequest(awswrappers.RequestMetric{Operation: "GetQueueUrl", Retries: 2, Service: "sqs"})
+			metrics.RecordRequest(awswrappers.RequestMetric{Failed: true, Operation: "GetQueueUrl", Service: "sqs", Throttled: true})
+
+			counters := metrics.Operation("sqs.GetQueueUrl")
+			if assert.NotNil(t, counters) {
+				assert.Equal(t, "2", counters.Get("requests").String())
+				assert.Equal(t, "2", counters.Get("retries").String())
+				assert.Equal(t, "1", counters.Get("errors").String())
+				assert.Equal(t, "1", counters.Get("throttles").String())
+			}
+			assert.Nil(t, metrics.Operation("sqs.SendMessage"))
+		})
+	})
+}
@@ -2,7 +2,6 @@ package rekognition
 
 import (
 	"github.com/aws/aws-sdk-go/aws/session"
-	rekognitionLib "github.com/aws/aws-sdk-go/service/rekognition"
 	"github.com/aws/aws-sdk-go/service/rekognition/rekognitioniface"
 )
 
@@ -13,13 +12,18 @@ type (
 	}
 )
 
-// NewClient creates a new wrapper based on the environment.
+// NewClient creates a new wrapper based on the environment, see
+// NewClientWithOptions for more settings.
 func NewClient(client rekognitioniface.RekognitionAPI) *Client {
-	if client == nil {
-		client = rekognitionLib.New(session.New())
-	}
+	return NewClientWithOptions(
+		WithAPI(client),
+	)
+}
 
-	return &Client{
-		client,
-	}
+// NewClientWithSession creates a new wrapper using the given session, see
+// awswrappers.NewSession.
+func NewClientWithSession(sess *session.Session) *Client {
+	return NewClientWithOptions(
+		WithSession(sess),
+	)
 }
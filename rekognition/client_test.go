@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	rekognitionLib "github.com/aws/aws-sdk-go/service/rekognition"
 )
 
@@ -16,7 +17,8 @@ type (
 	MockRekognitionClient struct {
 		rekognitioniface.RekognitionAPI
 
-		MockCompareFaces func(input *rekognitionLib.CompareFacesInput) (*rekognitionLib.CompareFacesOutput, error)
+		MockCompareFaces    func(input *rekognitionLib.CompareFacesInput) (*rekognitionLib.CompareFacesOutput, error)
+		MockListCollections func(input *rekognitionLib.ListCollectionsInput) (*rekognitionLib.ListCollectionsOutput, error)
 	}
 )
 
@@ -28,6 +30,18 @@ func (m MockRekognitionClient) CompareFaces(input *rekognitionLib.CompareFacesIn
 	return nil, nil
 }
 
+func (m MockRekognitionClient) ListCollectionsWithContext(ctx aws.Context, input *rekognitionLib.ListCollectionsInput, opts ...request.Option) (*rekognitionLib.ListCollectionsOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.MockListCollections != nil {
+		return m.MockListCollections(input)
+	}
+
+	return &rekognitionLib.ListCollectionsOutput{}, nil
+}
+
 func NewTestClient(mockClient *MockRekognitionClient) *rekognition.Client {
 	if mockClient == nil {
 		mockClient = &MockRekognitionClient{}
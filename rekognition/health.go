@@ -0,0 +1,21 @@
+package rekognition
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	rekognitionLib "github.com/aws/aws-sdk-go/service/rekognition"
+	"github.com/pkg/errors"
+)
+
+// HealthCheck checks Rekognition can be reached by listing a collection.
+func (c Client) HealthCheck(ctx context.Context) error {
+	_, err := c.RekognitionAPI.ListCollectionsWithContext(ctx, &rekognitionLib.ListCollectionsInput{
+		MaxResults: aws.Int64(1),
+	})
+	if err != nil {
+		return errors.Wrap(err, "Unable to list Rekognition collections")
+	}
+
+	return nil
+}
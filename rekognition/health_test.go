@@ -0,0 +1,47 @@
+package rekognition_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	rekognitionLib "github.com/aws/aws-sdk-go/service/rekognition"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/rekognition"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run(".HealthCheck()", func(t *testing.T) {
+		var cases = []struct {
+			name          string
+			err           error
+			expectedError string
+		}{
+			{"PassesWhenReachable", nil, ""},
+			{"ReturnsErrorWhenUnreachable", errors.New("Unavailable"), "Unable to list Rekognition collections: Unavailable"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				called := false
+
+				mockClient := &MockRekognitionClient{
+					MockListCollections: func(input *rekognitionLib.ListCollectionsInput) (*rekognitionLib.ListCollectionsOutput, error) {
+						called = true
+						return &rekognitionLib.ListCollectionsOutput{}, c.err
+					},
+				}
+
+				client := rekognition.NewClientWithOptions(rekognition.WithAPI(mockClient))
+				err := client.HealthCheck(context.Background())
+
+				assert.True(t, called)
+				if c.expectedError == "" {
+					assert.NoError(t, err)
+				} else {
+					assert.EqualError(t, err, c.expectedError)
+				}
+			})
+		}
+	})
+}
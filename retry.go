@@ -0,0 +1,23 @@
+package awswrappers
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// IsRetryableError reports whether a failed request is worth retrying,
+// server errors, throttling and connection errors are but errors such as a
+// missing resource, denied access or an invalid request aren't. Errors that
+// don't come from the SDK are assumed to be retryable.
+func IsRetryableError(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return true
+	}
+
+	if requestFailure, ok := err.(awserr.RequestFailure); ok && requestFailure.StatusCode() >= 500 {
+		return true
+	}
+
+	return request.IsErrorRetryable(awsErr) || request.IsErrorThrottle(awsErr)
+}
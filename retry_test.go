@@ -0,0 +1,31 @@
+package awswrappers_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	var cases = []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{"NonAWSError", errors.New("Client error"), true},
+		{"ServerError", awserr.NewRequestFailure(awserr.New("InternalError", "", nil), 500, ""), true},
+		{"Throttling", awserr.New("ThrottlingException", "", nil), true},
+		{"ConnectionError", awserr.New("RequestError", "", nil), true},
+		{"ValidationError", awserr.NewRequestFailure(awserr.New("ValidationException", "", nil), 400, ""), false},
+		{"AccessDenied", awserr.New("AccessDenied", "", nil), false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.retryable, awswrappers.IsRetryableError(c.err))
+		})
+	}
+}
@@ -1,9 +1,7 @@
 package s3
 
 import (
-	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
-	s3Lib "github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 )
 
@@ -11,33 +9,27 @@ type (
 	// Client wraps the receive and delete functionality of s3.
 	Client struct {
 		s3iface.S3API
-		clientConfig *ClientConfig
+		clientConfig       *ClientConfig
+		healthCheckBuckets []string
 	}
 )
 
-// NewClient creates a new wrapper based on the environment.
+// NewClient creates a new wrapper based on the environment, see
+// NewClientWithOptions for more settings.
 func NewClient(config *ClientConfig, useDevelopmentClient bool, client s3iface.S3API) *Client {
-	if client == nil {
-		var s3Client *s3Lib.S3
-
-		if useDevelopmentClient {
-			config := &aws.Config{
-				Endpoint:         aws.String(config.Endpoint),
-				S3ForcePathStyle: aws.Bool(true),
-			}
-			s3Client = s3Lib.New(session.New(), config)
-		} else {
-			s3Client = s3Lib.New(session.New())
-		}
-
-		return &Client{
-			s3Client,
-			config,
-		}
-	}
+	return NewClientWithOptions(
+		WithAPI(client),
+		WithClientConfig(config),
+		WithDevelopmentMode(useDevelopmentClient),
+	)
+}
 
-	return &Client{
-		client,
-		config,
-	}
+// NewClientWithSession creates a new wrapper using the given session, see
+// awswrappers.NewSession.
+func NewClientWithSession(config *ClientConfig, useDevelopmentClient bool, sess *session.Session) *Client {
+	return NewClientWithOptions(
+		WithClientConfig(config),
+		WithDevelopmentMode(useDevelopmentClient),
+		WithSession(sess),
+	)
 }
@@ -0,0 +1,33 @@
+package s3
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	s3Lib "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+)
+
+// HealthCheck checks S3 can be reached, checking the buckets set with
+// WithHealthCheckBuckets exist or listing buckets when none are set.
+func (c Client) HealthCheck(ctx context.Context) error {
+	if len(c.healthCheckBuckets) == 0 {
+		_, err := c.S3API.ListBucketsWithContext(ctx, &s3Lib.ListBucketsInput{})
+		if err != nil {
+			return errors.Wrap(err, "Unable to list S3 buckets")
+		}
+
+		return nil
+	}
+
+	for _, bucket := range c.healthCheckBuckets {
+		_, err := c.S3API.HeadBucketWithContext(ctx, &s3Lib.HeadBucketInput{
+			Bucket: aws.String(bucket),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Unable to find S3 bucket '%s'", bucket)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package s3_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	s3Lib "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/s3"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run(".HealthCheck()", func(t *testing.T) {
+		t.Run("ListsBucketsWithoutHealthCheckBuckets", func(t *testing.T) {
+			listed := false
+
+			mockClient := &MockS3Client{
+				mockListBuckets: func(input *s3Lib.ListBucketsInput) (*s3Lib.ListBucketsOutput, error) {
+					listed = true
+					return &s3Lib.ListBucketsOutput{}, nil
+				},
+			}
+
+			client := s3.NewClientWithOptions(s3.WithAPI(mockClient))
+			err := client.HealthCheck(context.Background())
+
+			assert.NoError(t, err)
+			assert.True(t, listed)
+		})
+
+		t.Run("ReturnsErrorForMissingBucket", func(t *testing.T) {
+			mockClient := &MockS3Client{
+				mockHeadBucket: func(input *s3Lib.HeadBucketInput) (*s3Lib.HeadBucketOutput, error) {
+					if *input.Bucket == "bar" {
+						return nil, errors.New("Not found")
+					}
+
+					return &s3Lib.HeadBucketOutput{}, nil
+				},
+			}
+
+			client := s3.NewClientWithOptions(
+				s3.WithAPI(mockClient),
+				s3.WithHealthCheckBuckets("foo", "bar"),
+			)
+			err := client.HealthCheck(context.Background())
+
+			assert.EqualError(t, err, "Unable to find S3 bucket 'bar': Not found")
+		})
+	})
+}
@@ -1,12 +1,14 @@
 package s3
 
 import (
+	"context"
 	"io"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	s3Lib "github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/vidsy/awswrappers"
 )
 
 type (
@@ -15,6 +17,7 @@ type (
 		Bucket string
 		Key    string
 		client s3iface.S3API
+		tracer awswrappers.Tracer
 	}
 )
 
@@ -24,17 +27,60 @@ func NewObject(bucket string, key string, client s3iface.S3API) Object {
 		Bucket: bucket,
 		Key:    key,
 		client: client,
+		tracer: awswrappers.NopTracer{},
 	}
 }
 
+// WithTracer returns a copy of the Object that starts a span for each call,
+// see awswrappers.Tracer.
+func (s Object) WithTracer(tracer awswrappers.Tracer) Object {
+	s.tracer = tracer
+	return s
+}
+
+// Delete removes the object from S3.
+func (s Object) Delete() error {
+	return s.DeleteWithContext(context.Background())
+}
+
+// DeleteWithContext removes the object from S3, the context can cancel the
+// request.
+func (s Object) DeleteWithContext(ctx context.Context) error {
+	ctx, span := s.startSpan(ctx, "Delete")
+
+	params := &s3Lib.DeleteObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(s.Key),
+	}
+
+	_, err := s.client.DeleteObjectWithContext(ctx, params)
+	awswrappers.EndSpan(span, err)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // Get returns the data for a given key.
 func (s Object) Get() (io.ReadCloser, error) {
+	return s.GetWithContext(context.Background())
+}
+
+// GetWithContext returns the data for a given key, the context can cancel
+// the request including reads of the returned body.
+func (s Object) GetWithContext(ctx context.Context) (io.ReadCloser, error) {
+	ctx, span := s.startSpan(ctx, "Get")
+
 	params := &s3Lib.GetObjectInput{
 		Bucket: aws.String(s.Bucket),
 		Key:    aws.String(s.Key),
 	}
 
-	resp, err := s.client.GetObject(params)
+	resp, err := s.client.GetObjectWithContext(ctx, params)
+	awswrappers.EndSpan(span, err)
+
 	if err != nil {
 		return nil, err
 	}
@@ -61,6 +107,14 @@ func (s Object) PresignedPutURI(expiration time.Duration) (string, error) {
 
 // Put puts the given data to the given key in S3.
 func (s Object) Put(body io.ReadSeeker, contentType string) error {
+	return s.PutWithContext(context.Background(), body, contentType)
+}
+
+// PutWithContext puts the given data to the given key in S3, the context can
+// cancel the request.
+func (s Object) PutWithContext(ctx context.Context, body io.ReadSeeker, contentType string) error {
+	ctx, span := s.startSpan(ctx, "Put")
+
 	params := &s3Lib.PutObjectInput{
 		Bucket:      aws.String(s.Bucket),
 		Key:         aws.String(s.Key),
@@ -68,7 +122,9 @@ func (s Object) Put(body io.ReadSeeker, contentType string) error {
 		Body:        body,
 	}
 
-	_, err := s.client.PutObject(params)
+	_, err := s.client.PutObjectWithContext(ctx, params)
+	awswrappers.EndSpan(span, err)
+
 	if err != nil {
 		return err
 	}
@@ -78,13 +134,23 @@ func (s Object) Put(body io.ReadSeeker, contentType string) error {
 
 // RangeGet returns the data for a given byte range.
 func (s Object) RangeGet(rangeHeader string) (io.ReadCloser, error) {
+	return s.RangeGetWithContext(context.Background(), rangeHeader)
+}
+
+// RangeGetWithContext returns the data for a given byte range, the context
+// can cancel the request including reads of the returned body.
+func (s Object) RangeGetWithContext(ctx context.Context, rangeHeader string) (io.ReadCloser, error) {
+	ctx, span := s.startSpan(ctx, "RangeGet")
+
 	params := &s3Lib.GetObjectInput{
 		Bucket: aws.String(s.Bucket),
 		Key:    aws.String(s.Key),
 		Range:  aws.String(rangeHeader),
 	}
 
-	resp, err := s.client.GetObject(params)
+	resp, err := s.client.GetObjectWithContext(ctx, params)
+	awswrappers.EndSpan(span, err)
+
 	if err != nil {
 		return nil, err
 	}
@@ -94,15 +160,38 @@ func (s Object) RangeGet(rangeHeader string) (io.ReadCloser, error) {
 
 // Size returns the size of an S3 object.
 func (s Object) Size() (int64, error) {
+	return s.SizeWithContext(context.Background())
+}
+
+// SizeWithContext returns the size of an S3 object, the context can cancel
+// the request.
+func (s Object) SizeWithContext(ctx context.Context) (int64, error) {
+	ctx, span := s.startSpan(ctx, "Size")
+
 	params := &s3Lib.HeadObjectInput{
 		Bucket: aws.String(s.Bucket),
 		Key:    aws.String(s.Key),
 	}
 
-	resp, err := s.client.HeadObject(params)
+	resp, err := s.client.HeadObjectWithContext(ctx, params)
+	awswrappers.EndSpan(span, err)
+
 	if err != nil {
 		return 0, err
 	}
 
 	return *resp.ContentLength, nil
 }
+
+func (s Object) startSpan(ctx context.Context, operation string) (context.Context, awswrappers.Span) {
+	tracer := s.tracer
+	if tracer == nil {
+		tracer = awswrappers.NopTracer{}
+	}
+
+	ctx, span := tracer.StartSpan(ctx, "s3."+operation)
+	span.SetAttribute("bucket", s.Bucket)
+	span.SetAttribute("key", s.Key)
+
+	return ctx, span
+}
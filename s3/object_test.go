@@ -2,6 +2,7 @@ package s3_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
 	s3Lib "github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
@@ -19,14 +21,57 @@ import (
 type (
 	MockS3Client struct {
 		s3iface.S3API
+		mockDeleteObject     func(*s3Lib.DeleteObjectInput) (*s3Lib.DeleteObjectOutput, error)
 		mockGetObject        func(*s3Lib.GetObjectInput) (*s3Lib.GetObjectOutput, error)
+		mockHeadBucket       func(*s3Lib.HeadBucketInput) (*s3Lib.HeadBucketOutput, error)
 		mockHeadObject       func(*s3Lib.HeadObjectInput) (*s3Lib.HeadObjectOutput, error)
+		mockListBuckets      func(*s3Lib.ListBucketsInput) (*s3Lib.ListBucketsOutput, error)
 		mockPutObject        func(*s3Lib.PutObjectInput) (*s3Lib.PutObjectOutput, error)
 		mockPutObjectRequest func(input *s3Lib.PutObjectInput) (*request.Request, *s3Lib.PutObjectOutput)
 	}
 )
 
-func (m MockS3Client) GetObject(input *s3Lib.GetObjectInput) (*s3Lib.GetObjectOutput, error) {
+func (m MockS3Client) HeadBucketWithContext(ctx aws.Context, input *s3Lib.HeadBucketInput, opts ...request.Option) (*s3Lib.HeadBucketOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.mockHeadBucket != nil {
+		return m.mockHeadBucket(input)
+	}
+
+	return &s3Lib.HeadBucketOutput{}, nil
+}
+
+func (m MockS3Client) ListBucketsWithContext(ctx aws.Context, input *s3Lib.ListBucketsInput, opts ...request.Option) (*s3Lib.ListBucketsOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.mockListBuckets != nil {
+		return m.mockListBuckets(input)
+	}
+
+	return &s3Lib.ListBucketsOutput{}, nil
+}
+
+func (m MockS3Client) DeleteObjectWithContext(ctx aws.Context, input *s3Lib.DeleteObjectInput, opts ...request.Option) (*s3Lib.DeleteObjectOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.mockDeleteObject != nil {
+		return m.mockDeleteObject(input)
+	}
+
+	return &s3Lib.DeleteObjectOutput{}, nil
+}
+
+func (m MockS3Client) GetObjectWithContext(ctx aws.Context, input *s3Lib.GetObjectInput, opts ...request.Option) (*s3Lib.GetObjectOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	if m.mockGetObject != nil {
 		return m.mockGetObject(input)
 	}
@@ -35,7 +80,11 @@ func (m MockS3Client) GetObject(input *s3Lib.GetObjectInput) (*s3Lib.GetObjectOu
 	return &s3Lib.GetObjectOutput{Body: body}, nil
 }
 
-func (m MockS3Client) HeadObject(input *s3Lib.HeadObjectInput) (*s3Lib.HeadObjectOutput, error) {
+func (m MockS3Client) HeadObjectWithContext(ctx aws.Context, input *s3Lib.HeadObjectInput, opts ...request.Option) (*s3Lib.HeadObjectOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	if m.mockHeadObject != nil {
 		return m.mockHeadObject(input)
 	}
@@ -53,7 +102,11 @@ func (m MockS3Client) PutObjectRequest(input *s3Lib.PutObjectInput) (*request.Re
 	return nil, nil
 }
 
-func (m MockS3Client) PutObject(input *s3Lib.PutObjectInput) (*s3Lib.PutObjectOutput, error) {
+func (m MockS3Client) PutObjectWithContext(ctx aws.Context, input *s3Lib.PutObjectInput, opts ...request.Option) (*s3Lib.PutObjectOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	if m.mockPutObject != nil {
 		return m.mockPutObject(input)
 	}
@@ -92,6 +145,44 @@ func TestObject(t *testing.T) {
 		})
 	})
 
+	t.Run("Delete()", func(t *testing.T) {
+		t.Run("DeletesObject", func(t *testing.T) {
+			var deleteInput *s3Lib.DeleteObjectInput
+			mockClient := &MockS3Client{
+				mockDeleteObject: func(input *s3Lib.DeleteObjectInput) (*s3Lib.DeleteObjectOutput, error) {
+					deleteInput = input
+					return &s3Lib.DeleteObjectOutput{}, nil
+				},
+			}
+			object := s3.NewObject(
+				"foo",
+				"bar",
+				mockClient,
+			)
+			err := object.Delete()
+
+			assert.NoError(t, err)
+			assert.Equal(t, "foo", *deleteInput.Bucket)
+			assert.Equal(t, "bar", *deleteInput.Key)
+		})
+
+		t.Run("ReturnsErrorOnClientError", func(t *testing.T) {
+			mockClient := &MockS3Client{
+				mockDeleteObject: func(*s3Lib.DeleteObjectInput) (*s3Lib.DeleteObjectOutput, error) {
+					return nil, errors.New("Delete object error")
+				},
+			}
+			object := s3.NewObject(
+				"foo",
+				"bar",
+				mockClient,
+			)
+			err := object.Delete()
+
+			assert.Error(t, err)
+		})
+	})
+
 	t.Run("Put()", func(t *testing.T) {
 		body := bytes.NewReader([]byte("test"))
 
@@ -195,5 +286,20 @@ func TestObject(t *testing.T) {
 
 			assert.NotNil(t, err)
 		})
+
+		t.Run("ReturnsErrorWhenContextCancelled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			object := s3.NewObject(
+				"foo",
+				"bar",
+				&MockS3Client{},
+			)
+
+			_, err := object.RangeGetWithContext(ctx, "range=0-100")
+
+			assert.Equal(t, context.Canceled, err)
+		})
 	})
 }
@@ -0,0 +1,133 @@
+package s3
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3Lib "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/vidsy/awswrappers"
+)
+
+type (
+	// Option configures a Client created by NewClientWithOptions.
+	Option func(*options)
+
+	options struct {
+		api                s3iface.S3API
+		config             *ClientConfig
+		developmentMode    bool
+		endpoint           string
+		healthCheckBuckets []string
+		logger             awswrappers.Logger
+		metrics            awswrappers.Metrics
+		session            *session.Session
+	}
+)
+
+// WithAPI uses the given S3 API instead of creating one, the session and
+// endpoint options are ignored.
+func WithAPI(api s3iface.S3API) Option {
+	return func(o *options) {
+		o.api = api
+	}
+}
+
+// WithClientConfig sets the config of the Client.
+func WithClientConfig(config *ClientConfig) Option {
+	return func(o *options) {
+		o.config = config
+	}
+}
+
+// WithDevelopmentMode sends requests to ClientConfig.Endpoint.
+func WithDevelopmentMode(developmentMode bool) Option {
+	return func(o *options) {
+		o.developmentMode = developmentMode
+	}
+}
+
+// WithEndpoint sends requests to the given endpoint, taking precedence over
+// ClientConfig.Endpoint in development mode.
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithHealthCheckBuckets sets the buckets checked by Client.HealthCheck.
+func WithHealthCheckBuckets(names ...string) Option {
+	return func(o *options) {
+		o.healthCheckBuckets = names
+	}
+}
+
+// WithLogger logs the requests made by the Client and its retries to the
+// given logger, it has no effect when the API is given with WithAPI.
+func WithLogger(logger awswrappers.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithMetrics records the requests made by the Client to the given
+// metrics, it has no effect when the API is given with WithAPI.
+func WithMetrics(metrics awswrappers.Metrics) Option {
+	return func(o *options) {
+		o.metrics = metrics
+	}
+}
+
+// WithSession creates the S3 API from the given session, see
+// awswrappers.NewSession.
+func WithSession(sess *session.Session) Option {
+	return func(o *options) {
+		o.session = sess
+	}
+}
+
+// NewClientWithOptions creates a new wrapper from the given options, by
+// default an empty ClientConfig and a new default session are used.
+func NewClientWithOptions(opts ...Option) *Client {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.config == nil {
+		o.config = &ClientConfig{}
+	}
+
+	if o.api == nil {
+		if o.session == nil {
+			o.session = session.New()
+		}
+
+		endpoint := o.endpoint
+		if endpoint == "" && o.developmentMode {
+			endpoint = o.config.Endpoint
+		}
+
+		var s3Client *s3Lib.S3
+
+		if endpoint != "" {
+			s3Client = s3Lib.New(o.session, awswrappers.DevelopmentConfig(endpoint).WithS3ForcePathStyle(true))
+		} else {
+			s3Client = s3Lib.New(o.session)
+		}
+
+		if o.logger != nil {
+			awswrappers.LogRequests(&s3Client.Handlers, o.logger)
+		}
+
+		if o.metrics != nil {
+			awswrappers.RecordRequests(&s3Client.Handlers, o.metrics)
+		}
+
+		o.api = s3Client
+	}
+
+	return &Client{
+		o.api,
+		o.config,
+		o.healthCheckBuckets,
+	}
+}
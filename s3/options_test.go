@@ -0,0 +1,29 @@
+package s3_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	s3Lib "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/s3"
+)
+
+func TestOptions(t *testing.T) {
+	t.Run(".NewClientWithOptions()", func(t *testing.T) {
+		t.Run("UsesGivenAPI", func(t *testing.T) {
+			mockClient := &MockS3Client{}
+			client := s3.NewClientWithOptions(s3.WithAPI(mockClient))
+
+			assert.Equal(t, mockClient, client.S3API)
+		})
+
+		t.Run("UsesPathStyleForEndpoint", func(t *testing.T) {
+			client := s3.NewClientWithOptions(s3.WithEndpoint("http://localhost:4572"))
+			s3Client := client.S3API.(*s3Lib.S3)
+
+			assert.Equal(t, "http://localhost:4572", s3Client.Endpoint)
+			assert.True(t, aws.BoolValue(s3Client.Config.S3ForcePathStyle))
+		})
+	})
+}
@@ -1,39 +1,51 @@
 package ses
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	sesLib "github.com/aws/aws-sdk-go/service/ses"
 	"github.com/aws/aws-sdk-go/service/ses/sesiface"
+	"github.com/vidsy/awswrappers"
 )
 
 type (
 	// Client wraps the receive and delete functionality of ses.
 	Client struct {
 		sesiface.SESAPI
+		tracer awswrappers.Tracer
 	}
 )
 
-// NewClient creates a new wrapper based on the environment.
+// NewClient creates a new wrapper based on the environment, see
+// NewClientWithOptions for more settings.
 func NewClient(client sesiface.SESAPI) *Client {
-	if client == nil {
-		var sesClient *sesLib.SES
-
-		sesClient = sesLib.New(session.New())
-
-		return &Client{
-			sesClient,
-		}
-	}
+	return NewClientWithOptions(
+		WithAPI(client),
+	)
+}
 
-	return &Client{
-		client,
-	}
+// NewClientWithSession creates a new wrapper using the given session, see
+// awswrappers.NewSession.
+func NewClientWithSession(sess *session.Session) *Client {
+	return NewClientWithOptions(
+		WithSession(sess),
+	)
 }
 
 // SendEmailMessage sends an email to the given recipient(s) and returns the message
 // ID.
 func (c Client) SendEmailMessage(recipients []string, from string, subject string, plainBody string, htmlBody string, replyTo string) (string, error) {
+	return c.SendEmailMessageWithContext(context.Background(), recipients, from, subject, plainBody, htmlBody, replyTo)
+}
+
+// SendEmailMessageWithContext sends an email to the given recipient(s) and
+// returns the message ID, the context can cancel the request.
+func (c Client) SendEmailMessageWithContext(ctx context.Context, recipients []string, from string, subject string, plainBody string, htmlBody string, replyTo string) (string, error) {
+	ctx, span := c.tracer.StartSpan(ctx, "ses.SendEmailMessage")
+	span.SetAttribute("recipients", len(recipients))
+
 	destination := &sesLib.Destination{
 		ToAddresses: aws.StringSlice(recipients),
 	}
@@ -62,10 +74,14 @@ func (c Client) SendEmailMessage(recipients []string, from string, subject strin
 		Source:           aws.String(from),
 	}
 
-	response, err := c.SendEmail(params)
+	response, err := c.SendEmailWithContext(ctx, params)
 	if err != nil {
+		awswrappers.EndSpan(span, err)
 		return "", err
 	}
 
+	span.SetAttribute("message_id", *response.MessageId)
+	span.End()
+
 	return *response.MessageId, nil
 }
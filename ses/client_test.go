@@ -1,11 +1,13 @@
 package ses_test
 
 import (
+	"context"
 	"testing"
 
 	"errors"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	sesLib "github.com/aws/aws-sdk-go/service/ses"
 	"github.com/aws/aws-sdk-go/service/ses/sesiface"
 	"github.com/vidsy/awswrappers/ses"
@@ -15,11 +17,16 @@ type (
 	MockSDKClient struct {
 		sesiface.SESAPI
 
-		mockSendEmail func(input *sesLib.SendEmailInput) (*sesLib.SendEmailOutput, error)
+		mockGetSendQuota func(input *sesLib.GetSendQuotaInput) (*sesLib.GetSendQuotaOutput, error)
+		mockSendEmail    func(input *sesLib.SendEmailInput) (*sesLib.SendEmailOutput, error)
 	}
 )
 
-func (m MockSDKClient) SendEmail(input *sesLib.SendEmailInput) (*sesLib.SendEmailOutput, error) {
+func (m MockSDKClient) SendEmailWithContext(ctx aws.Context, input *sesLib.SendEmailInput, opts ...request.Option) (*sesLib.SendEmailOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	if m.mockSendEmail != nil {
 		return m.mockSendEmail(input)
 	}
@@ -27,6 +34,18 @@ func (m MockSDKClient) SendEmail(input *sesLib.SendEmailInput) (*sesLib.SendEmai
 	return nil, nil
 }
 
+func (m MockSDKClient) GetSendQuotaWithContext(ctx aws.Context, input *sesLib.GetSendQuotaInput, opts ...request.Option) (*sesLib.GetSendQuotaOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.mockGetSendQuota != nil {
+		return m.mockGetSendQuota(input)
+	}
+
+	return &sesLib.GetSendQuotaOutput{}, nil
+}
+
 func NewTestClient(mockClient *MockSDKClient) *ses.Client {
 	if mockClient == nil {
 		mockClient = &MockSDKClient{}
@@ -87,5 +106,25 @@ func TestClient(t *testing.T) {
 				t.Fatal("Expected .Publish() to return an error", err)
 			}
 		})
+
+		t.Run("ReturnsErrorWhenContextCancelled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			client := NewTestClient(nil)
+			_, err := client.SendEmailMessageWithContext(
+				ctx,
+				[]string{"im_a_creator@example.com"},
+				"admin@vidsy.com",
+				"An email",
+				"Plain body",
+				"<b>Html body</b>",
+				"vidsy@intercom.com",
+			)
+
+			if err != context.Canceled {
+				t.Fatalf("Expected .SendEmailMessageWithContext() to return '%s', got: '%v'", context.Canceled, err)
+			}
+		})
 	})
 }
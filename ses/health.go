@@ -0,0 +1,18 @@
+package ses
+
+import (
+	"context"
+
+	sesLib "github.com/aws/aws-sdk-go/service/ses"
+	"github.com/pkg/errors"
+)
+
+// HealthCheck checks SES can be reached by fetching the send quota.
+func (c Client) HealthCheck(ctx context.Context) error {
+	_, err := c.SESAPI.GetSendQuotaWithContext(ctx, &sesLib.GetSendQuotaInput{})
+	if err != nil {
+		return errors.Wrap(err, "Unable to get SES send quota")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package ses_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sesLib "github.com/aws/aws-sdk-go/service/ses"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/ses"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run(".HealthCheck()", func(t *testing.T) {
+		var cases = []struct {
+			name          string
+			err           error
+			expectedError string
+		}{
+			{"PassesWhenReachable", nil, ""},
+			{"ReturnsErrorWhenUnreachable", errors.New("Unavailable"), "Unable to get SES send quota: Unavailable"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				called := false
+
+				mockClient := &MockSDKClient{
+					mockGetSendQuota: func(input *sesLib.GetSendQuotaInput) (*sesLib.GetSendQuotaOutput, error) {
+						called = true
+						return &sesLib.GetSendQuotaOutput{}, c.err
+					},
+				}
+
+				client := ses.NewClientWithOptions(ses.WithAPI(mockClient))
+				err := client.HealthCheck(context.Background())
+
+				assert.True(t, called)
+				if c.expectedError == "" {
+					assert.NoError(t, err)
+				} else {
+					assert.EqualError(t, err, c.expectedError)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package ses
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	sesLib "github.com/aws/aws-sdk-go/service/ses"
+	"github.com/aws/aws-sdk-go/service/ses/sesiface"
+	"github.com/vidsy/awswrappers"
+)
+
+type (
+	// Option configures a Client created by NewClientWithOptions.
+	Option func(*options)
+
+	options struct {
+		api      sesiface.SESAPI
+		endpoint string
+		logger   awswrappers.Logger
+		metrics  awswrappers.Metrics
+		session  *session.Session
+		tracer   awswrappers.Tracer
+	}
+)
+
+// WithAPI uses the given SES API instead of creating one, the session and
+// endpoint options are ignored.
+func WithAPI(api sesiface.SESAPI) Option {
+	return func(o *options) {
+		o.api = api
+	}
+}
+
+// WithEndpoint sends requests to the given endpoint.
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithLogger logs the requests made by the Client and its retries to the
+// given logger, it has no effect when the API is given with WithAPI.
+func WithLogger(logger awswrappers.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithMetrics records the requests made by the Client to the given
+// metrics, it has no effect when the API is given with WithAPI.
+func WithMetrics(metrics awswrappers.Metrics) Option {
+	return func(o *options) {
+		o.metrics = metrics
+	}
+}
+
+// WithSession creates the SES API from the given session, see
+// awswrappers.NewSession.
+func WithSession(sess *session.Session) Option {
+	return func(o *options) {
+		o.session = sess
+	}
+}
+
+// WithTracer starts a span for each call made with the Client, see
+// awswrappers.Tracer.
+func WithTracer(tracer awswrappers.Tracer) Option {
+	return func(o *options) {
+		o.tracer = tracer
+	}
+}
+
+// NewClientWithOptions creates a new wrapper from the given options, by
+// default a new default session is used.
+func NewClientWithOptions(opts ...Option) *Client {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	tracer := o.tracer
+	if tracer == nil {
+		tracer = awswrappers.NopTracer{}
+	}
+
+	if o.api == nil {
+		if o.session == nil {
+			o.session = session.New()
+		}
+
+		var sesClient *sesLib.SES
+
+		if o.endpoint != "" {
+			sesClient = sesLib.New(o.session, awswrappers.DevelopmentConfig(o.endpoint))
+		} else {
+			sesClient = sesLib.New(o.session)
+		}
+
+		if o.logger != nil {
+			awswrappers.LogRequests(&sesClient.Handlers, o.logger)
+		}
+
+		if o.metrics != nil {
+			awswrappers.RecordRequests(&sesClient.Handlers, o.metrics)
+		}
+
+		o.api = sesClient
+	}
+
+	return &Client{
+		o.api,
+		tracer,
+	}
+}
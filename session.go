@@ -0,0 +1,65 @@
+package awswrappers
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/pkg/errors"
+)
+
+// NewSession creates an AWS session from the config, which can be passed to
+// the NewClientWithSession constructor of each package. A nil config returns
+// a session using the SDK defaults.
+func NewSession(config *SessionConfig) (*session.Session, error) {
+	if config == nil {
+		config = &SessionConfig{}
+	}
+
+	awsConfig := aws.NewConfig()
+
+	if config.Region != "" {
+		awsConfig = awsConfig.WithRegion(config.Region)
+	}
+
+	if config.MaxRetries > 0 {
+		awsConfig = awsConfig.WithMaxRetries(config.MaxRetries)
+	}
+
+	if config.HTTPClient != nil {
+		awsConfig = awsConfig.WithHTTPClient(config.HTTPClient)
+	}
+
+	if config.AccessKeyID != "" || config.SecretAccessKey != "" {
+		awsConfig = awsConfig.WithCredentials(
+			credentials.NewStaticCredentials(config.AccessKeyID, config.SecretAccessKey, ""),
+		)
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsConfig,
+		Profile:           config.Profile,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to create AWS session")
+	}
+
+	if config.RoleARN == "" {
+		return sess, nil
+	}
+
+	roleCredentials := stscreds.NewCredentials(sess, config.RoleARN, func(provider *stscreds.AssumeRoleProvider) {
+		if config.ExternalID != "" {
+			provider.ExternalID = aws.String(config.ExternalID)
+		}
+	})
+
+	return sess.Copy(aws.NewConfig().WithCredentials(roleCredentials)), nil
+}
+
+// DevelopmentConfig returns the config used by the clients in development
+// mode to send requests to a local endpoint.
+func DevelopmentConfig(endpoint string) *aws.Config {
+	return aws.NewConfig().WithEndpoint(endpoint)
+}
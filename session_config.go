@@ -0,0 +1,77 @@
+package awswrappers
+
+import (
+	"net/http"
+
+	"github.com/vidsy/go-kmsconfig/kmsconfig"
+)
+
+type (
+	// SessionConfig stores the values used by NewSession to build the AWS
+	// session shared by the clients. Every value is optional, empty values
+	// fall back to the SDK defaults (environment, shared config and instance
+	// role). AccessKeyID and SecretAccessKey are intended for local stand-ins
+	// of AWS services, ExternalID is only used when RoleARN is set.
+	SessionConfig struct {
+		AccessKeyID     string
+		ExternalID      string
+		HTTPClient      *http.Client
+		MaxRetries      int
+		Profile         string
+		Region          string
+		RoleARN         string
+		SecretAccessKey string
+	}
+)
+
+// NewSessionConfigFromKMSConfig creates a new session config based on config
+// values for the current environment. Every key of the aws node is required,
+// optional settings are left to the SDK by an empty string or a max_retries
+// of 0 or less. The secret_access_key is encrypted, and the HTTP client can
+// only be set in code.
+func NewSessionConfigFromKMSConfig(config kmsconfig.ConfigInterrogator) (*SessionConfig, error) {
+	region, err := config.String("aws", "region")
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := config.String("aws", "profile")
+	if err != nil {
+		return nil, err
+	}
+
+	roleARN, err := config.String("aws", "role_arn")
+	if err != nil {
+		return nil, err
+	}
+
+	externalID, err := config.String("aws", "external_id")
+	if err != nil {
+		return nil, err
+	}
+
+	accessKeyID, err := config.String("aws", "access_key_id")
+	if err != nil {
+		return nil, err
+	}
+
+	secretAccessKey, err := config.EncryptedString("aws", "secret_access_key")
+	if err != nil {
+		return nil, err
+	}
+
+	maxRetries, err := config.Integer("aws", "max_retries")
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionConfig{
+		AccessKeyID:     accessKeyID,
+		ExternalID:      externalID,
+		MaxRetries:      maxRetries,
+		Profile:         profile,
+		Region:          region,
+		RoleARN:         roleARN,
+		SecretAccessKey: secretAccessKey,
+	}, nil
+}
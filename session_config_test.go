@@ -0,0 +1,47 @@
+package awswrappers_test
+
+import (
+	"testing"
+
+	"github.com/vidsy/awswrappers"
+)
+
+func TestSessionConfig(t *testing.T) {
+	t.Run("NewSessionConfigFromKMSConfig", func(t *testing.T) {
+		t.Run("CreatesWithValidConfig", func(t *testing.T) {
+			sessionConfig, _ := awswrappers.NewSessionConfigFromKMSConfig(&awswrappers.MockConfig{})
+
+			if sessionConfig == nil {
+				t.Fatalf("Expected new SessionConfig, got: %v", sessionConfig)
+			}
+		})
+
+		t.Run("ReturnsErrorWhenInvalidConfig", func(t *testing.T) {
+			var errorCases = []struct {
+				valueType string
+				key       string
+			}{
+				{"string", "region"},
+				{"string", "profile"},
+				{"string", "role_arn"},
+				{"string", "external_id"},
+				{"string", "access_key_id"},
+				{"encryptedString", "secret_access_key"},
+				{"integer", "max_retries"},
+			}
+
+			for _, errorCase := range errorCases {
+				mockConfig := awswrappers.NewErrorConfig(errorCase.valueType, errorCase.key)
+				_, err := awswrappers.NewSessionConfigFromKMSConfig(mockConfig)
+
+				if err == nil {
+					t.Errorf(
+						"Expected error when config value '%s' is invalid, got: %s",
+						errorCase.key,
+						err,
+					)
+				}
+			}
+		})
+	})
+}
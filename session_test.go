@@ -0,0 +1,72 @@
+package awswrappers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers"
+)
+
+func TestSession(t *testing.T) {
+	t.Run(".NewSession()", func(t *testing.T) {
+		t.Run("AppliesConfig", func(t *testing.T) {
+			httpClient := &http.Client{}
+
+			sess, err := awswrappers.NewSession(&awswrappers.SessionConfig{
+				AccessKeyID:     "access-key-id",
+				HTTPClient:      httpClient,
+				MaxRetries:      5,
+				Region:          "eu-west-1",
+				SecretAccessKey: "secret-access-key",
+			})
+			assert.NoError(t, err)
+
+			credentials, err := sess.Config.Credentials.Get()
+			assert.NoError(t, err)
+
+			assert.Equal(t, "eu-west-1", aws.StringValue(sess.Config.Region))
+			assert.Equal(t, 5, aws.IntValue(sess.Config.MaxRetries))
+			assert.Equal(t, httpClient, sess.Config.HTTPClient)
+			assert.Equal(t, "access-key-id", credentials.AccessKeyID)
+			assert.Equal(t, "secret-access-key", credentials.SecretAccessKey)
+		})
+
+		t.Run("AssumesRole", func(t *testing.T) {
+			sess, err := awswrappers.NewSession(&awswrappers.SessionConfig{
+				AccessKeyID:     "access-key-id",
+				ExternalID:      "external-id",
+				Region:          "eu-west-1",
+				RoleARN:         "arn:aws:iam::000000000000:role/test",
+				SecretAccessKey: "secret-access-key",
+			})
+			assert.NoError(t, err)
+
+			baseSession, err := awswrappers.NewSession(&awswrappers.SessionConfig{
+				AccessKeyID:     "access-key-id",
+				Region:          "eu-west-1",
+				SecretAccessKey: "secret-access-key",
+			})
+			assert.NoError(t, err)
+
+			assert.NotEqual(t, baseSession.Config.Credentials, sess.Config.Credentials)
+			assert.Equal(t, "eu-west-1", aws.StringValue(sess.Config.Region))
+		})
+
+		t.Run("UsesDefaultsForNilConfig", func(t *testing.T) {
+			sess, err := awswrappers.NewSession(nil)
+
+			assert.NoError(t, err)
+			assert.NotNil(t, sess)
+		})
+	})
+
+	t.Run(".DevelopmentConfig()", func(t *testing.T) {
+		t.Run("SetsEndpoint", func(t *testing.T) {
+			config := awswrappers.DevelopmentConfig("http://localhost:4576")
+
+			assert.Equal(t, "http://localhost:4576", aws.StringValue(config.Endpoint))
+		})
+	})
+}
@@ -1,10 +1,13 @@
 package sns
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	snsLib "github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/vidsy/awswrappers"
 )
 
 const (
@@ -22,49 +25,71 @@ type (
 	Client struct {
 		snsiface.SNSAPI
 		clientConfig *ClientConfig
+		tracer       awswrappers.Tracer
 	}
 )
 
-// NewClient creates a new wrapper based on the environment.
+// NewClient creates a new wrapper based on the environment, see
+// NewClientWithOptions for more settings.
 func NewClient(config *ClientConfig, useDevelopmentClient bool, client snsiface.SNSAPI) *Client {
-	if client == nil {
-		var snsClient *snsLib.SNS
-
-		if useDevelopmentClient {
-			snsClient = snsLib.New(session.New(), aws.NewConfig().WithEndpoint(config.Endpoint))
-		} else {
-			snsClient = snsLib.New(session.New())
-		}
-
-		return &Client{
-			snsClient,
-			config,
-		}
-	}
+	return NewClientWithOptions(
+		WithAPI(client),
+		WithClientConfig(config),
+		WithDevelopmentMode(useDevelopmentClient),
+	)
+}
 
-	return &Client{
-		client,
-		config,
-	}
+// NewClientWithSession creates a new wrapper using the given session, see
+// awswrappers.NewSession.
+func NewClientWithSession(config *ClientConfig, useDevelopmentClient bool, sess *session.Session) *Client {
+	return NewClientWithOptions(
+		WithClientConfig(config),
+		WithDevelopmentMode(useDevelopmentClient),
+		WithSession(sess),
+	)
 }
 
 // PublishMessage sends a message to a SNS topic.
 func (c Client) PublishMessage(message string, topicARN string) (string, error) {
+	return c.PublishMessageWithContext(context.Background(), message, topicARN)
+}
+
+// PublishMessageWithContext sends a message to a SNS topic, the context can
+// cancel the request. The trace context of the span is propagated in the
+// message attributes.
+func (c Client) PublishMessageWithContext(ctx context.Context, message string, topicARN string) (string, error) {
+	ctx, span := c.tracer.StartSpan(ctx, "sns.PublishMessage")
+	span.SetAttribute("topic", topicARN)
+
 	params := &snsLib.PublishInput{
-		Message:  aws.String(message),
-		TopicArn: aws.String(topicARN),
+		Message:           aws.String(message),
+		MessageAttributes: c.traceContextAttributes(ctx),
+		TopicArn:          aws.String(topicARN),
 	}
 
-	response, err := c.Publish(params)
+	response, err := c.PublishWithContext(ctx, params)
 	if err != nil {
+		awswrappers.EndSpan(span, err)
 		return "", err
 	}
 
+	span.SetAttribute("message_id", *response.MessageId)
+	span.End()
+
 	return *response.MessageId, nil
 }
 
 // SendSMSMessage sends an SMS message and returns the MessageID.
 func (c Client) SendSMSMessage(number string, from string, messageType string, message string) (string, error) {
+	return c.SendSMSMessageWithContext(context.Background(), number, from, messageType, message)
+}
+
+// SendSMSMessageWithContext sends an SMS message and returns the MessageID,
+// the context can cancel the request.
+func (c Client) SendSMSMessageWithContext(ctx context.Context, number string, from string, messageType string, message string) (string, error) {
+	ctx, span := c.tracer.StartSpan(ctx, "sns.SendSMSMessage")
+	span.SetAttribute("sms_type", messageType)
+
 	messageAttributes := map[string]*snsLib.MessageAttributeValue{
 		"AWS.SNS.SMS.SenderID": &snsLib.MessageAttributeValue{
 			DataType:    aws.String("String"),
@@ -82,10 +107,35 @@ func (c Client) SendSMSMessage(number string, from string, messageType string, m
 		PhoneNumber:       aws.String(number),
 	}
 
-	response, err := c.Publish(params)
+	response, err := c.PublishWithContext(ctx, params)
 	if err != nil {
+		awswrappers.EndSpan(span, err)
 		return "", err
 	}
 
+	span.SetAttribute("message_id", *response.MessageId)
+	span.End()
+
 	return *response.MessageId, nil
 }
+
+// traceContextAttributes returns the trace context of the span in the
+// context as message attributes, or nil if there is none.
+func (c Client) traceContextAttributes(ctx context.Context) map[string]*snsLib.MessageAttributeValue {
+	carrier := map[string]string{}
+	c.tracer.Inject(ctx, carrier)
+
+	if len(carrier) == 0 {
+		return nil
+	}
+
+	messageAttributes := make(map[string]*snsLib.MessageAttributeValue, len(carrier))
+	for key, value := range carrier {
+		messageAttributes[key] = &snsLib.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+
+	return messageAttributes
+}
@@ -1,14 +1,18 @@
 package sns_test
 
 import (
+	"context"
 	"testing"
 
 	"errors"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	snsLib "github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers"
 	"github.com/vidsy/awswrappers/sns"
 )
 
@@ -16,11 +20,16 @@ type (
 	MockSDKClient struct {
 		snsiface.SNSAPI
 
-		mockPublish func(input *snsLib.PublishInput) (*snsLib.PublishOutput, error)
+		mockListTopics func(input *snsLib.ListTopicsInput) (*snsLib.ListTopicsOutput, error)
+		mockPublish    func(input *snsLib.PublishInput) (*snsLib.PublishOutput, error)
 	}
 )
 
-func (m MockSDKClient) Publish(input *snsLib.PublishInput) (*snsLib.PublishOutput, error) {
+func (m MockSDKClient) PublishWithContext(ctx aws.Context, input *snsLib.PublishInput, opts ...request.Option) (*snsLib.PublishOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	if m.mockPublish != nil {
 		return m.mockPublish(input)
 	}
@@ -28,6 +37,18 @@ func (m MockSDKClient) Publish(input *snsLib.PublishInput) (*snsLib.PublishOutpu
 	return nil, nil
 }
 
+func (m MockSDKClient) ListTopicsWithContext(ctx aws.Context, input *snsLib.ListTopicsInput, opts ...request.Option) (*snsLib.ListTopicsOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.mockListTopics != nil {
+		return m.mockListTopics(input)
+	}
+
+	return &snsLib.ListTopicsOutput{}, nil
+}
+
 func NewTestClient(mockClient *MockSDKClient) *sns.Client {
 	if mockClient == nil {
 		mockClient = &MockSDKClient{}
@@ -111,5 +132,47 @@ func TestClient(t *testing.T) {
 
 			assert.Error(t, err)
 		})
+
+		t.Run("ReturnsErrorWhenContextCancelled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			client := NewTestClient(nil)
+			_, err := client.PublishMessageWithContext(
+				ctx, `{"foo":"bar"}`, "8da92fa4-3913-4300-9f3b-31de66e27a97",
+			)
+
+			assert.Equal(t, context.Canceled, err)
+		})
+
+		t.Run("PropagatesTraceContext", func(t *testing.T) {
+			var publishInput *snsLib.PublishInput
+			mockClient := &MockSDKClient{
+				mockPublish: func(input *snsLib.PublishInput) (*snsLib.PublishOutput, error) {
+					publishInput = input
+					return &snsLib.PublishOutput{
+						MessageId: aws.String("123"),
+					}, nil
+				},
+			}
+
+			tracer := awswrappers.NewInMemoryTracer()
+			client := sns.NewClientWithOptions(sns.WithAPI(mockClient), sns.WithTracer(tracer))
+			_, err := client.PublishMessage(
+				`{"foo":"bar"}`, "8da92fa4-3913-4300-9f3b-31de66e27a97",
+			)
+
+			assert.NoError(t, err)
+
+			spans := tracer.Spans()
+			if assert.Len(t, spans, 1) {
+				assert.Equal(t, "sns.PublishMessage", spans[0].Name)
+				assert.Equal(t, "123", spans[0].Attributes["message_id"])
+				assert.Equal(t,
+					fmt.Sprintf("00-%s-%s-01", spans[0].TraceID, spans[0].ID),
+					*publishInput.MessageAttributes[awswrappers.TraceParentKey].StringValue,
+				)
+			}
+		})
 	})
 }
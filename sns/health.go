@@ -0,0 +1,18 @@
+package sns
+
+import (
+	"context"
+
+	snsLib "github.com/aws/aws-sdk-go/service/sns"
+	"github.com/pkg/errors"
+)
+
+// HealthCheck checks SNS can be reached by listing topics.
+func (c Client) HealthCheck(ctx context.Context) error {
+	_, err := c.SNSAPI.ListTopicsWithContext(ctx, &snsLib.ListTopicsInput{})
+	if err != nil {
+		return errors.Wrap(err, "Unable to list SNS topics")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package sns_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	snsLib "github.com/aws/aws-sdk-go/service/sns"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/sns"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run(".HealthCheck()", func(t *testing.T) {
+		var cases = []struct {
+			name          string
+			err           error
+			expectedError string
+		}{
+			{"PassesWhenReachable", nil, ""},
+			{"ReturnsErrorWhenUnreachable", errors.New("Unavailable"), "Unable to list SNS topics: Unavailable"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				called := false
+
+				mockClient := &MockSDKClient{
+					mockListTopics: func(input *snsLib.ListTopicsInput) (*snsLib.ListTopicsOutput, error) {
+						called = true
+						return &snsLib.ListTopicsOutput{}, c.err
+					},
+				}
+
+				client := sns.NewClientWithOptions(sns.WithAPI(mockClient))
+				err := client.HealthCheck(context.Background())
+
+				assert.True(t, called)
+				if c.expectedError == "" {
+					assert.NoError(t, err)
+				} else {
+					assert.EqualError(t, err, c.expectedError)
+				}
+			})
+		}
+	})
+}
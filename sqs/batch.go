@@ -0,0 +1,375 @@
+package sqs
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+	"github.com/vidsy/awswrappers"
+	"github.com/vidsy/backoff"
+)
+
+const (
+	batchMaxEntries     = 10
+	batchMaxConcurrency = 10
+	batchMaxPayloadSize = 256 * 1024
+)
+
+type (
+	// BatchEntryResult represents the outcome of a single entry within a
+	// batch request, Index is the position of the entry in the slice given to
+	// the batch method.
+	BatchEntryResult struct {
+		Index       int
+		MessageID   string
+		Code        string
+		Message     string
+		SenderFault bool
+	}
+
+	// BatchReport separates the entries of a batch request that succeeded
+	// from those that still failed after retrying.
+	BatchReport struct {
+		Successful []BatchEntryResult
+		Failed     []BatchEntryResult
+	}
+
+	batchChunkRequest func(indices []int) ([]BatchEntryResult, []BatchEntryResult, error)
+)
+
+var (
+	batchRetryIntervals = []int{0, 100, 200, 400, 800}
+)
+
+// SendMessageBatch sends the given message bodies to the queue in chunks of
+// up to 10 messages and 256 KiB, chunks are sent in parallel and entries
+// that fail are retried with backoff unless the failure was caused by the
+// sender.
+func (s Client) SendMessageBatch(queueName string, bodies [][]byte) (*BatchReport, error) {
+	return s.SendMessageBatchWithContext(context.Background(), queueName, bodies)
+}
+
+// SendMessageBatchWithContext is SendMessageBatch with a context, once the
+// context is cancelled failed entries are no longer retried.
+func (s Client) SendMessageBatchWithContext(ctx context.Context, queueName string, bodies [][]byte) (*BatchReport, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "sqs.SendMessageBatch")
+	span.SetAttribute("queue", queueName)
+	span.SetAttribute("entries", len(bodies))
+
+	report, err := s.sendMessageBatch(ctx, queueName, bodies)
+	awswrappers.EndSpan(span, err)
+
+	return report, err
+}
+
+func (s Client) sendMessageBatch(ctx context.Context, queueName string, bodies [][]byte) (*BatchReport, error) {
+	queueURL, err := s.queueURL(ctx, queueName)
+	if err != nil {
+		return nil, err
+	}
+
+	messageBodies := make([]string, len(bodies))
+	messageAttributes := make([]map[string]*sqsLib.MessageAttributeValue, len(bodies))
+	sizes := make([]int, len(bodies))
+
+	for i, body := range bodies {
+		messageBodies[i], messageAttributes[i], err = s.offloadPayload(ctx, body, s.injectTraceContext(ctx, nil))
+		if err != nil {
+			for j := 0; j < i; j++ {
+				s.discardPayload(ctx, messageBodies[j], messageAttributes[j])
+			}
+
+			return nil, err
+		}
+
+		sizes[i] = messageSize([]byte(messageBodies[i]), messageAttributes[i])
+	}
+
+	report := performBatch(ctx, sizes, func(indices []int) ([]BatchEntryResult, []BatchEntryResult, error) {
+		entries := make([]*sqsLib.SendMessageBatchRequestEntry, 0, len(indices))
+		for _, index := range indices {
+			entries = append(entries, &sqsLib.SendMessageBatchRequestEntry{
+				Id:                aws.String(strconv.Itoa(index)),
+				MessageAttributes: messageAttributes[index],
+				MessageBody:       aws.String(messageBodies[index]),
+			})
+		}
+
+		output, err := s.SQSAPI.SendMessageBatchWithContext(ctx, &sqsLib.SendMessageBatchInput{
+			Entries:  entries,
+			QueueUrl: aws.String(queueURL),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		successful := make([]BatchEntryResult, 0, len(output.Successful))
+		for _, entry := range output.Successful {
+			successful = append(successful, BatchEntryResult{
+				Index:     batchEntryIndex(entry.Id),
+				MessageID: aws.StringValue(entry.MessageId),
+			})
+		}
+
+		return successful, batchFailedResults(output.Failed), nil
+	})
+
+	s.logBatchFailures("SendMessageBatch", queueName, report)
+
+	for _, result := range report.Failed {
+		s.discardPayload(ctx, messageBodies[result.Index], messageAttributes[result.Index])
+	}
+
+	return report, nil
+}
+
+// DeleteMessageBatch removes the messages with the given receipt handles in
+// chunks of 10, chunks are deleted in parallel and entries that fail are
+// retried with backoff unless the failure was caused by the sender. Bodies
+// stored by the LargePayloadStore are deleted for successful entries.
+func (s Client) DeleteMessageBatch(queueName string, receiptHandles []*string) (*BatchReport, error) {
+	return s.DeleteMessageBatchWithContext(context.Background(), queueName, receiptHandles)
+}
+
+// DeleteMessageBatchWithContext is DeleteMessageBatch with a context, once
+// the context is cancelled failed entries are no longer retried.
+func (s Client) DeleteMessageBatchWithContext(ctx context.Context, queueName string, receiptHandles []*string) (*BatchReport, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "sqs.DeleteMessageBatch")
+	span.SetAttribute("queue", queueName)
+	span.SetAttribute("entries", len(receiptHandles))
+
+	report, err := s.deleteMessageBatch(ctx, queueName, receiptHandles)
+	awswrappers.EndSpan(span, err)
+
+	return report, err
+}
+
+func (s Client) deleteMessageBatch(ctx context.Context, queueName string, receiptHandles []*string) (*BatchReport, error) {
+	queueURL, err := s.queueURL(ctx, queueName)
+	if err != nil {
+		return nil, err
+	}
+
+	pointers := make([]*payloadPointer, len(receiptHandles))
+	sqsReceiptHandles := make([]*string, len(receiptHandles))
+
+	for i, receiptHandle := range receiptHandles {
+		pointers[i], sqsReceiptHandles[i] = decodeReceiptHandle(receiptHandle)
+	}
+
+	report := performBatch(ctx, make([]int, len(receiptHandles)), func(indices []int) ([]BatchEntryResult, []BatchEntryResult, error) {
+		entries := make([]*sqsLib.DeleteMessageBatchRequestEntry, 0, len(indices))
+		for _, index := range indices {
+			entries = append(entries, &sqsLib.DeleteMessageBatchRequestEntry{
+				Id:            aws.String(strconv.Itoa(index)),
+				ReceiptHandle: sqsReceiptHandles[index],
+			})
+		}
+
+		output, err := s.SQSAPI.DeleteMessageBatchWithContext(ctx, &sqsLib.DeleteMessageBatchInput{
+			Entries:  entries,
+			QueueUrl: aws.String(queueURL),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		successful := make([]BatchEntryResult, 0, len(output.Successful))
+		for _, entry := range output.Successful {
+			successful = append(successful, BatchEntryResult{
+				Index: batchEntryIndex(entry.Id),
+			})
+		}
+
+		return successful, batchFailedResults(output.Failed), nil
+	})
+
+	s.logBatchFailures("DeleteMessageBatch", queueName, report)
+
+	var firstErr error
+	failedPayloads := 0
+	payloads := 0
+
+	for _, result := range report.Successful {
+		if pointers[result.Index] != nil {
+			payloads++
+		}
+
+		err := s.deletePayload(ctx, pointers[result.Index])
+		if err != nil {
+			s.logger.Log("Unable to delete large payload of deleted message", awswrappers.Fields{
+				"error": err.Error(),
+				"queue": queueName,
+			})
+
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			failedPayloads++
+		}
+	}
+
+	if firstErr != nil {
+		return report, errors.Wrapf(
+			firstErr,
+			"Problem deleting %d of %d large payloads, the first error was",
+			failedPayloads,
+			payloads,
+		)
+	}
+
+	return report, nil
+}
+
+func (s Client) logBatchFailures(operation string, queueName string, report *BatchReport) {
+	if len(report.Failed) == 0 {
+		return
+	}
+
+	codes := make([]string, 0, len(report.Failed))
+	for _, result := range report.Failed {
+		codes = append(codes, result.Code)
+	}
+
+	s.logger.Log("SQS batch entries failed", awswrappers.Fields{
+		"error_codes": codes,
+		"failed":      len(report.Failed),
+		"operation":   operation,
+		"queue":       queueName,
+		"service":     sqsLib.ServiceName,
+		"successful":  len(report.Successful),
+	})
+}
+
+// performBatch splits the entries into chunks of up to 10 entries whose
+// sizes add up to at most 256 KiB, sizes holds the payload size of each
+// entry.
+func performBatch(ctx context.Context, sizes []int, request batchChunkRequest) *BatchReport {
+	report := &BatchReport{
+		Successful: []BatchEntryResult{},
+		Failed:     []BatchEntryResult{},
+	}
+
+	var reportMutex sync.Mutex
+	var chunksWaitGroup sync.WaitGroup
+	semaphore := make(chan struct{}, batchMaxConcurrency)
+
+	for start := 0; start < len(sizes); {
+		end := start + 1
+		size := sizes[start]
+
+		for end < len(sizes) && end-start < batchMaxEntries && size+sizes[end] <= batchMaxPayloadSize {
+			size += sizes[end]
+			end++
+		}
+
+		indices := make([]int, 0, end-start)
+		for index := start; index < end; index++ {
+			indices = append(indices, index)
+		}
+
+		start = end
+
+		chunksWaitGroup.Add(1)
+		semaphore <- struct{}{}
+
+		go func(indices []int) {
+			defer chunksWaitGroup.Done()
+			defer func() { <-semaphore }()
+
+			successful, failed := performBatchChunk(ctx, indices, request)
+
+			reportMutex.Lock()
+			defer reportMutex.Unlock()
+
+			report.Successful = append(report.Successful, successful...)
+			report.Failed = append(report.Failed, failed...)
+		}(indices)
+	}
+
+	chunksWaitGroup.Wait()
+
+	sort.Slice(report.Successful, func(i, j int) bool {
+		return report.Successful[i].Index < report.Successful[j].Index
+	})
+	sort.Slice(report.Failed, func(i, j int) bool {
+		return report.Failed[i].Index < report.Failed[j].Index
+	})
+
+	return report
+}
+
+func performBatchChunk(ctx context.Context, indices []int, request batchChunkRequest) ([]BatchEntryResult, []BatchEntryResult) {
+	var successful []BatchEntryResult
+	var failed []BatchEntryResult
+	var retryable []BatchEntryResult
+	pending := indices
+
+	bp := backoff.Policy{
+		Intervals: batchRetryIntervals,
+	}
+
+	bp.Perform(func() (bool, error) {
+		chunkSuccessful, chunkFailed, err := request(pending)
+		if err != nil {
+			code := ""
+			if awsErr, ok := err.(awserr.Error); ok {
+				code = awsErr.Code()
+			}
+
+			retryable = make([]BatchEntryResult, 0, len(pending))
+			for _, index := range pending {
+				retryable = append(retryable, BatchEntryResult{
+					Index:   index,
+					Code:    code,
+					Message: err.Error(),
+				})
+			}
+
+			return !awswrappers.IsRetryableError(err) || ctx.Err() != nil, nil
+		}
+
+		successful = append(successful, chunkSuccessful...)
+		pending = nil
+		retryable = nil
+
+		for _, result := range chunkFailed {
+			if result.SenderFault {
+				failed = append(failed, result)
+				continue
+			}
+
+			pending = append(pending, result.Index)
+			retryable = append(retryable, result)
+		}
+
+		return len(pending) == 0 || ctx.Err() != nil, nil
+	})
+
+	return successful, append(failed, retryable...)
+}
+
+func batchFailedResults(entries []*sqsLib.BatchResultErrorEntry) []BatchEntryResult {
+	failed := make([]BatchEntryResult, 0, len(entries))
+	for _, entry := range entries {
+		failed = append(failed, BatchEntryResult{
+			Index:       batchEntryIndex(entry.Id),
+			Code:        aws.StringValue(entry.Code),
+			Message:     aws.StringValue(entry.Message),
+			SenderFault: aws.BoolValue(entry.SenderFault),
+		})
+	}
+
+	return failed
+}
+
+func batchEntryIndex(id *string) int {
+	index, _ := strconv.Atoi(aws.StringValue(id))
+	return index
+}
@@ -0,0 +1,272 @@
+package sqs_test
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers"
+	"github.com/vidsy/awswrappers/sqs"
+)
+
+func TestBatch(t *testing.T) {
+	t.Run(".SendMessageBatch()", func(t *testing.T) {
+		t.Run("ChunksIntoTenEntries", func(t *testing.T) {
+			var mutex sync.Mutex
+			var chunkSizes []int
+
+			mock := MockSDKClient{
+				mockSendMessageBatch: func(input *sqsLib.SendMessageBatchInput) (*sqsLib.SendMessageBatchOutput, error) {
+					mutex.Lock()
+					chunkSizes = append(chunkSizes, len(input.Entries))
+					mutex.Unlock()
+
+					return successfulSendMessageBatchOutput(input.Entries), nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			report, err := client.SendMessageBatch("foo", generateBodies(25))
+
+			sort.Ints(chunkSizes)
+
+			assert.NoError(t, err)
+			assert.Equal(t, []int{5, 10, 10}, chunkSizes)
+			assert.Len(t, report.Successful, 25)
+			assert.Empty(t, report.Failed)
+			assert.Equal(t, 0, report.Successful[0].Index)
+			assert.Equal(t, "message_id_24", report.Successful[24].MessageID)
+		})
+
+		t.Run("ChunksByPayloadSize", func(t *testing.T) {
+			var mutex sync.Mutex
+			var chunkSizes []int
+
+			mock := MockSDKClient{
+				mockSendMessageBatch: func(input *sqsLib.SendMessageBatchInput) (*sqsLib.SendMessageBatchOutput, error) {
+					mutex.Lock()
+					chunkSizes = append(chunkSizes, len(input.Entries))
+					mutex.Unlock()
+
+					return successfulSendMessageBatchOutput(input.Entries), nil
+				},
+			}
+
+			body := make([]byte, 100*1024)
+			client := NewTestClient(&mock)
+			report, err := client.SendMessageBatch("foo", [][]byte{body, body, body, body, body})
+
+			sort.Ints(chunkSizes)
+
+			assert.NoError(t, err)
+			assert.Equal(t, []int{1, 2, 2}, chunkSizes)
+			assert.Len(t, report.Successful, 5)
+		})
+
+		t.Run("RetriesFailedEntries", func(t *testing.T) {
+			callCount := 0
+
+			mock := MockSDKClient{
+				mockSendMessageBatch: func(input *sqsLib.SendMessageBatchInput) (*sqsLib.SendMessageBatchOutput, error) {
+					callCount++
+
+					if callCount == 1 {
+						output := successfulSendMessageBatchOutput(input.Entries[1:])
+						output.Failed = []*sqsLib.BatchResultErrorEntry{
+							{
+								Code:        aws.String("InternalError"),
+								Id:          input.Entries[0].Id,
+								SenderFault: aws.Bool(false),
+							},
+						}
+
+						return output, nil
+					}
+
+					return successfulSendMessageBatchOutput(input.Entries), nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			report, err := client.SendMessageBatch("foo", generateBodies(5))
+
+			assert.NoError(t, err)
+			assert.Equal(t, 2, callCount)
+			assert.Len(t, report.Successful, 5)
+			assert.Empty(t, report.Failed)
+		})
+
+		t.Run("DoesNotRetrySenderFaults", func(t *testing.T) {
+			callCount := 0
+
+			mock := MockSDKClient{
+				mockSendMessageBatch: func(input *sqsLib.SendMessageBatchInput) (*sqsLib.SendMessageBatchOutput, error) {
+					callCount++
+
+					output := successfulSendMessageBatchOutput(input.Entries[1:])
+					output.Failed = []*sqsLib.BatchResultErrorEntry{
+						{
+							Code:        aws.String("InvalidMessageContents"),
+							Id:          input.Entries[0].Id,
+							SenderFault: aws.Bool(true),
+						},
+					}
+
+					return output, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			report, err := client.SendMessageBatch("foo", generateBodies(3))
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, callCount)
+			assert.Len(t, report.Successful, 2)
+			if assert.Len(t, report.Failed, 1) {
+				assert.Equal(t, 0, report.Failed[0].Index)
+				assert.Equal(t, "InvalidMessageContents", report.Failed[0].Code)
+			}
+		})
+
+		t.Run("ReportsEntriesFailedByClientError", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockSendMessageBatch: func(input *sqsLib.SendMessageBatchInput) (*sqsLib.SendMessageBatchOutput, error) {
+					return nil, errors.New("Client error")
+				},
+			}
+
+			client := NewTestClient(&mock)
+			report, err := client.SendMessageBatch("foo", generateBodies(3))
+
+			assert.NoError(t, err)
+			assert.Empty(t, report.Successful)
+			if assert.Len(t, report.Failed, 3) {
+				assert.Equal(t, "Client error", report.Failed[0].Message)
+			}
+		})
+		t.Run("DoesNotRetryNonRetryableErrors", func(t *testing.T) {
+			callCount := 0
+
+			mock := MockSDKClient{
+				mockSendMessageBatch: func(input *sqsLib.SendMessageBatchInput) (*sqsLib.SendMessageBatchOutput, error) {
+					callCount++
+					return nil, awserr.New(sqsLib.ErrCodeQueueDoesNotExist, "The specified queue does not exist", nil)
+				},
+			}
+
+			client := NewTestClient(&mock)
+			report, err := client.SendMessageBatch("foo", generateBodies(3))
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, callCount)
+			if assert.Len(t, report.Failed, 3) {
+				assert.Equal(t, sqsLib.ErrCodeQueueDoesNotExist, report.Failed[0].Code)
+			}
+		})
+
+		t.Run("LogsFailedEntries", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockSendMessageBatch: func(input *sqsLib.SendMessageBatchInput) (*sqsLib.SendMessageBatchOutput, error) {
+					output := successfulSendMessageBatchOutput(input.Entries[1:])
+					output.Failed = []*sqsLib.BatchResultErrorEntry{
+						{
+							Code:        aws.String("InvalidMessageContents"),
+							Id:          input.Entries[0].Id,
+							SenderFault: aws.Bool(true),
+						},
+					}
+
+					return output, nil
+				},
+			}
+
+			var mutex sync.Mutex
+			logged := map[string]awswrappers.Fields{}
+
+			client := sqs.NewClientWithOptions(
+				sqs.WithAPI(&mock),
+				sqs.WithClientConfig(&sqs.ClientConfig{QueueEndpoint: "http://www.test.com"}),
+				sqs.WithDevelopmentMode(true),
+				sqs.WithLogger(awswrappers.LoggerFunc(func(message string, fields awswrappers.Fields) {
+					mutex.Lock()
+					defer mutex.Unlock()
+
+					logged[message] = fields
+				})),
+			)
+			_, err := client.SendMessageBatch("foo", generateBodies(3))
+
+			assert.NoError(t, err)
+			if assert.Contains(t, logged, "SQS batch entries failed") {
+				assert.Equal(t, 1, logged["SQS batch entries failed"]["failed"])
+				assert.Equal(t, "foo", logged["SQS batch entries failed"]["queue"])
+			}
+			assert.Contains(t, logged, "Using development queue URL after failing to resolve it")
+		})
+	})
+
+	t.Run(".DeleteMessageBatch()", func(t *testing.T) {
+		t.Run("DeletesAllReceiptHandles", func(t *testing.T) {
+			var mutex sync.Mutex
+			var deletedHandles []string
+
+			mock := MockSDKClient{
+				mockDeleteMessageBatch: func(input *sqsLib.DeleteMessageBatchInput) (*sqsLib.DeleteMessageBatchOutput, error) {
+					output := &sqsLib.DeleteMessageBatchOutput{}
+
+					mutex.Lock()
+					defer mutex.Unlock()
+
+					for _, entry := range input.Entries {
+						deletedHandles = append(deletedHandles, *entry.ReceiptHandle)
+						output.Successful = append(output.Successful, &sqsLib.DeleteMessageBatchResultEntry{
+							Id: entry.Id,
+						})
+					}
+
+					return output, nil
+				},
+			}
+
+			var receiptHandles []*string
+			for i := 0; i < 12; i++ {
+				receiptHandles = append(receiptHandles, aws.String(fmt.Sprintf("handle_%d", i)))
+			}
+
+			client := NewTestClient(&mock)
+			report, err := client.DeleteMessageBatch("foo", receiptHandles)
+
+			assert.NoError(t, err)
+			assert.Len(t, deletedHandles, 12)
+			assert.Len(t, report.Successful, 12)
+			assert.Empty(t, report.Failed)
+		})
+	})
+}
+
+func generateBodies(count int) [][]byte {
+	bodies := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		bodies = append(bodies, []byte(fmt.Sprintf("message_%d", i)))
+	}
+
+	return bodies
+}
+
+func successfulSendMessageBatchOutput(entries []*sqsLib.SendMessageBatchRequestEntry) *sqsLib.SendMessageBatchOutput {
+	output := &sqsLib.SendMessageBatchOutput{}
+	for _, entry := range entries {
+		output.Successful = append(output.Successful, &sqsLib.SendMessageBatchResultEntry{
+			Id:        entry.Id,
+			MessageId: aws.String(fmt.Sprintf("message_id_%s", *entry.Id)),
+		})
+	}
+
+	return output
+}
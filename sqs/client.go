@@ -1,12 +1,13 @@
 package sqs
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
-
-	"fmt"
+	"github.com/vidsy/awswrappers"
 )
 
 type (
@@ -14,121 +15,357 @@ type (
 	ClientWrapper interface {
 		DeleteMessage(queueName string, receiptHandle *string) error
 		ReceiveMessage(queueName string) (*sqsLib.Message, error)
+		ReceiveMessages(queueName string) ([]*sqsLib.Message, error)
 		SendNewMessage(queueName string, body []byte) (string, error)
 	}
 
 	// Client wraps the receive and delete functionality of SQS.
 	Client struct {
 		sqsiface.SQSAPI
-		clientConfig *ClientConfig
+		clientConfig      *ClientConfig
+		developmentMode   bool
+		queueURLs         *queueURLCache
+		payloadStore      *LargePayloadStore
+		logger            awswrappers.Logger
+		tracer            awswrappers.Tracer
+		healthCheckQueues []string
 	}
 )
 
-// NewClient creates a new wrapper based on the environment.
+// NewClient creates a new wrapper based on the environment, see
+// NewClientWithOptions for more settings.
 func NewClient(config *ClientConfig, useDevelopmentClient bool, client sqsiface.SQSAPI) *Client {
-	if client == nil {
-		var sqsClient *sqsLib.SQS
+	return NewClientWithOptions(
+		WithAPI(client),
+		WithClientConfig(config),
+		WithDevelopmentMode(useDevelopmentClient),
+	)
+}
 
-		if useDevelopmentClient {
-			sqsClient = sqsLib.New(session.New(), aws.NewConfig().WithEndpoint(config.QueueEndpoint))
-		} else {
-			sqsClient = sqsLib.New(session.New())
-		}
+// NewClientWithSession creates a new wrapper using the given session, see
+// awswrappers.NewSession.
+func NewClientWithSession(config *ClientConfig, useDevelopmentClient bool, sess *session.Session) *Client {
+	return NewClientWithOptions(
+		WithClientConfig(config),
+		WithDevelopmentMode(useDevelopmentClient),
+		WithSession(sess),
+	)
+}
 
-		return &Client{
-			sqsClient,
-			config,
-		}
+// ReceiveMessage Returns a message from SQS, use ReceiveMessages when
+// ClientConfig.MaxNumberOfMessages is greater than 1 as any other messages
+// in the batch are not returned.
+func (s Client) ReceiveMessage(queueName string) (*sqsLib.Message, error) {
+	return s.ReceiveMessageWithContext(context.Background(), queueName)
+}
+
+// ReceiveMessageWithContext is ReceiveMessage with a context, cancelling the
+// context stops a long poll.
+func (s Client) ReceiveMessageWithContext(ctx context.Context, queueName string) (*sqsLib.Message, error) {
+	messages, err := s.ReceiveMessagesWithContext(ctx, queueName)
+	if err != nil {
+		return nil, err
 	}
 
-	return &Client{
-		client,
-		config,
+	if len(messages) > 0 {
+		return messages[0], nil
 	}
+
+	return nil, nil
 }
 
-// ReceiveMessage Returns a message from SQS.
-func (s Client) ReceiveMessage(queueName string) (*sqsLib.Message, error) {
-	resp, err := s.SQSAPI.ReceiveMessage(s.receiveMessageParams(queueName))
+// ReceiveMessages returns up to ClientConfig.MaxNumberOfMessages messages
+// from SQS, resolving any bodies stored by the LargePayloadStore. Messages
+// sent by SendDelayed that aren't due yet are re-enqueued and left out.
+// Messages that can't be re-enqueued or whose body can't be resolved are
+// logged and left out, the rest of the messages are still returned.
+func (s Client) ReceiveMessages(queueName string) ([]*sqsLib.Message, error) {
+	return s.ReceiveMessagesWithContext(context.Background(), queueName)
+}
+
+// ReceiveMessagesWithContext is ReceiveMessages with a context, cancelling
+// the context stops a long poll.
+func (s Client) ReceiveMessagesWithContext(ctx context.Context, queueName string) ([]*sqsLib.Message, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "sqs.ReceiveMessages")
+	span.SetAttribute("queue", queueName)
+
+	messages, err := s.receiveMessages(ctx, queueName)
+	span.SetAttribute("messages", len(messages))
+	awswrappers.EndSpan(span, err)
+
+	return messages, err
+}
+
+func (s Client) receiveMessages(ctx context.Context, queueName string) ([]*sqsLib.Message, error) {
+	params, err := s.receiveMessageParams(ctx, queueName)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(resp.Messages) > 0 {
-		return resp.Messages[0], nil
+	resp, err := s.SQSAPI.ReceiveMessageWithContext(ctx, params)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, nil
+	messages := make([]*sqsLib.Message, 0, len(resp.Messages))
+
+	for _, message := range resp.Messages {
+		deferred, err := s.deferDelayedMessage(ctx, queueName, message)
+		if err != nil {
+			s.logSkippedMessage(queueName, message, err)
+			continue
+		}
+
+		if deferred {
+			continue
+		}
+
+		err = s.resolvePayload(ctx, message)
+		if err != nil {
+			s.logSkippedMessage(queueName, message, err)
+			continue
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+// logSkippedMessage logs a received message that is left out of the
+// messages returned, it becomes visible again once its visibility timeout
+// expires so it can still move to a dead letter queue.
+func (s Client) logSkippedMessage(queueName string, message *sqsLib.Message, err error) {
+	s.logger.Log("Skipping SQS message that couldn't be received", awswrappers.Fields{
+		"error":      err.Error(),
+		"message_id": aws.StringValue(message.MessageId),
+		"queue":      queueName,
+		"service":    sqsLib.ServiceName,
+	})
 }
 
 // SendNewMessage sends an SQS message on the given queue.
 func (s Client) SendNewMessage(queueName string, body []byte) (string, error) {
+	return s.SendNewMessageWithContext(context.Background(), queueName, body)
+}
+
+// SendNewMessageWithContext sends an SQS message on the given queue, the
+// context can cancel the request.
+func (s Client) SendNewMessageWithContext(ctx context.Context, queueName string, body []byte) (string, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "sqs.SendNewMessage")
+	span.SetAttribute("queue", queueName)
+
+	messageID, err := s.sendNewMessage(ctx, queueName, body)
+	if err == nil {
+		span.SetAttribute("message_id", messageID)
+	}
+
+	awswrappers.EndSpan(span, err)
+
+	return messageID, err
+}
+
+func (s Client) sendNewMessage(ctx context.Context, queueName string, body []byte) (string, error) {
+	queueURL, err := s.queueURL(ctx, queueName)
+	if err != nil {
+		return "", err
+	}
+
+	messageBody, messageAttributes, err := s.offloadPayload(ctx, body, s.injectTraceContext(ctx, nil))
+	if err != nil {
+		return "", err
+	}
+
 	params := &sqsLib.SendMessageInput{
-		MessageBody: aws.String(string(body[:])),
-		QueueUrl:    aws.String(s.queueURL(queueName)),
+		MessageAttributes: messageAttributes,
+		MessageBody:       aws.String(messageBody),
+		QueueUrl:          aws.String(queueURL),
 	}
 
-	resp, err := s.SQSAPI.SendMessage(params)
+	resp, err := s.SQSAPI.SendMessageWithContext(ctx, params)
 	if err != nil {
+		s.discardPayload(ctx, messageBody, messageAttributes)
 		return "", err
 	}
 
 	return *resp.MessageId, nil
 }
 
-// SendNewFIFOMessage sends an SQS message on the given FIFO queue.
+// SendNewFIFOMessage sends an SQS message on the given FIFO queue, an empty
+// deduplicationID is omitted for queues using content based deduplication.
 func (s Client) SendNewFIFOMessage(queueName string, body []byte, deduplicationID string, groupID string, messageAttributes map[string]*sqsLib.MessageAttributeValue) (string, error) {
+	return s.SendNewFIFOMessageWithContext(context.Background(), queueName, body, deduplicationID, groupID, messageAttributes)
+}
+
+// SendNewFIFOMessageWithContext is SendNewFIFOMessage with a context that
+// can cancel the request.
+func (s Client) SendNewFIFOMessageWithContext(ctx context.Context, queueName string, body []byte, deduplicationID string, groupID string, messageAttributes map[string]*sqsLib.MessageAttributeValue) (string, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "sqs.SendNewFIFOMessage")
+	span.SetAttribute("queue", queueName)
+	span.SetAttribute("group_id", groupID)
+
+	messageID, err := s.sendNewFIFOMessage(ctx, queueName, body, deduplicationID, groupID, messageAttributes)
+	if err == nil {
+		span.SetAttribute("message_id", messageID)
+	}
+
+	awswrappers.EndSpan(span, err)
+
+	return messageID, err
+}
+
+func (s Client) sendNewFIFOMessage(ctx context.Context, queueName string, body []byte, deduplicationID string, groupID string, messageAttributes map[string]*sqsLib.MessageAttributeValue) (string, error) {
+	err := ValidateFIFOQueueName(queueName)
+	if err != nil {
+		return "", err
+	}
+
+	queueURL, err := s.queueURL(ctx, queueName)
+	if err != nil {
+		return "", err
+	}
+
+	messageBody, messageAttributes, err := s.offloadPayload(ctx, body, s.injectTraceContext(ctx, messageAttributes))
+	if err != nil {
+		return "", err
+	}
+
 	params := &sqsLib.SendMessageInput{
-		MessageBody:            aws.String(string(body[:])),
-		MessageDeduplicationId: aws.String(deduplicationID),
-		MessageGroupId:         aws.String(groupID),
-		QueueUrl:               aws.String(s.queueURL(queueName)),
+		MessageBody:    aws.String(messageBody),
+		MessageGroupId: aws.String(groupID),
+		QueueUrl:       aws.String(queueURL),
+	}
+
+	if deduplicationID != "" {
+		params.MessageDeduplicationId = aws.String(deduplicationID)
 	}
 
 	if messageAttributes != nil {
 		params.MessageAttributes = messageAttributes
 	}
 
-	resp, err := s.SQSAPI.SendMessage(params)
+	resp, err := s.SQSAPI.SendMessageWithContext(ctx, params)
 	if err != nil {
+		s.discardPayload(ctx, messageBody, messageAttributes)
 		return "", err
 	}
 
 	return *resp.MessageId, nil
 }
 
-// DeleteMessage removes a message based on the recipt handle.
+// DeleteMessage removes a message based on the recipt handle, along with its
+// body if it was stored by the LargePayloadStore.
 func (s Client) DeleteMessage(queueName string, receiptHandle *string) error {
-	_, err := s.SQSAPI.DeleteMessage(s.deleteMessageParams(queueName, receiptHandle))
+	return s.DeleteMessageWithContext(context.Background(), queueName, receiptHandle)
+}
+
+// DeleteMessageWithContext is DeleteMessage with a context that can cancel
+// the requests.
+func (s Client) DeleteMessageWithContext(ctx context.Context, queueName string, receiptHandle *string) error {
+	ctx, span := s.tracer.StartSpan(ctx, "sqs.DeleteMessage")
+	span.SetAttribute("queue", queueName)
+
+	err := s.deleteMessage(ctx, queueName, receiptHandle)
+	awswrappers.EndSpan(span, err)
+
+	return err
+}
+
+func (s Client) deleteMessage(ctx context.Context, queueName string, receiptHandle *string) error {
+	pointer, receiptHandle := decodeReceiptHandle(receiptHandle)
+
+	params, err := s.deleteMessageParams(ctx, queueName, receiptHandle)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	_, err = s.SQSAPI.DeleteMessageWithContext(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	return s.deletePayload(ctx, pointer)
+}
+
+// ChangeVisibilityTimeout sets the visibility timeout of a received message
+// to the given number of seconds from now.
+func (s Client) ChangeVisibilityTimeout(queueName string, receiptHandle *string, visibilityTimeout int64) error {
+	return s.ChangeVisibilityTimeoutWithContext(context.Background(), queueName, receiptHandle, visibilityTimeout)
+}
+
+// ChangeVisibilityTimeoutWithContext is ChangeVisibilityTimeout with a
+// context that can cancel the request.
+func (s Client) ChangeVisibilityTimeoutWithContext(ctx context.Context, queueName string, receiptHandle *string, visibilityTimeout int64) error {
+	ctx, span := s.tracer.StartSpan(ctx, "sqs.ChangeVisibilityTimeout")
+	span.SetAttribute("queue", queueName)
+	span.SetAttribute("visibility_timeout", visibilityTimeout)
+
+	err := s.changeVisibilityTimeout(ctx, queueName, receiptHandle, visibilityTimeout)
+	awswrappers.EndSpan(span, err)
+
+	return err
 }
 
-func (s Client) queueURL(queueName string) string {
-	return fmt.Sprintf("%s/%s", s.clientConfig.QueueEndpoint, queueName)
+func (s Client) changeVisibilityTimeout(ctx context.Context, queueName string, receiptHandle *string, visibilityTimeout int64) error {
+	_, receiptHandle = decodeReceiptHandle(receiptHandle)
+
+	queueURL, err := s.queueURL(ctx, queueName)
+	if err != nil {
+		return err
+	}
+
+	params := &sqsLib.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(queueURL),
+		ReceiptHandle:     receiptHandle,
+		VisibilityTimeout: aws.Int64(visibilityTimeout),
+	}
+
+	_, err = s.SQSAPI.ChangeMessageVisibilityWithContext(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
 
-func (s Client) deleteMessageParams(queueName string, receiptHandle *string) *sqsLib.DeleteMessageInput {
+func (s Client) deleteMessageParams(ctx context.Context, queueName string, receiptHandle *string) (*sqsLib.DeleteMessageInput, error) {
+	queueURL, err := s.queueURL(ctx, queueName)
+	if err != nil {
+		return nil, err
+	}
+
 	return &sqsLib.DeleteMessageInput{
-		QueueUrl:      aws.String(s.queueURL(queueName)),
+		QueueUrl:      aws.String(queueURL),
 		ReceiptHandle: receiptHandle,
-	}
+	}, nil
 }
 
-func (s Client) receiveMessageParams(queueName string) *sqsLib.ReceiveMessageInput {
-	return &sqsLib.ReceiveMessageInput{
-		QueueUrl: aws.String(s.queueURL(queueName)),
+func (s Client) receiveMessageParams(ctx context.Context, queueName string) (*sqsLib.ReceiveMessageInput, error) {
+	queueURL, err := s.queueURL(ctx, queueName)
+	if err != nil {
+		return nil, err
+	}
+
+	maxNumberOfMessages := s.clientConfig.MaxNumberOfMessages
+	if maxNumberOfMessages <= 0 {
+		maxNumberOfMessages = 1
+	}
+
+	params := &sqsLib.ReceiveMessageInput{
+		QueueUrl: aws.String(queueURL),
 		AttributeNames: []*string{
 			aws.String("All"),
 		},
-		MaxNumberOfMessages: aws.Int64(s.clientConfig.MaxNumberOfMessages),
+		MaxNumberOfMessages: aws.Int64(maxNumberOfMessages),
 		MessageAttributeNames: []*string{
 			aws.String("All"),
 		},
-		VisibilityTimeout: aws.Int64(s.clientConfig.VisibilityTimeout),
-		WaitTimeSeconds:   aws.Int64(s.clientConfig.WaitTimeSeconds),
+		WaitTimeSeconds: aws.Int64(s.clientConfig.WaitTimeSeconds),
 	}
+
+	if s.clientConfig.VisibilityTimeout > 0 {
+		params.VisibilityTimeout = aws.Int64(s.clientConfig.VisibilityTimeout)
+	}
+
+	return params, nil
 }
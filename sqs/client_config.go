@@ -3,12 +3,16 @@ package sqs
 import "github.com/vidsy/go-kmsconfig/kmsconfig"
 
 type (
-	// ClientConfig store config values for the Client.
+	// ClientConfig store config values for the Client, QueueOwnerAWSAccountID
+	// is optional and only needed when the queues belong to another account.
+	// A MaxNumberOfMessages of 0 receives a single message and a
+	// VisibilityTimeout of 0 uses the visibility timeout of the queue.
 	ClientConfig struct {
-		QueueEndpoint       string
-		MaxNumberOfMessages int64
-		VisibilityTimeout   int64
-		WaitTimeSeconds     int64
+		QueueEndpoint          string
+		MaxNumberOfMessages    int64
+		VisibilityTimeout      int64
+		WaitTimeSeconds        int64
+		QueueOwnerAWSAccountID string
 	}
 )
 
@@ -36,9 +40,9 @@ func NewClientConfigFromKMSConfig(config kmsconfig.ConfigInterrogator) (*ClientC
 	}
 
 	return &ClientConfig{
-		endpointURL,
-		int64(maxNumberOfmessages),
-		int64(visibilityTimeout),
-		int64(waitTimeSeconds),
+		QueueEndpoint:       endpointURL,
+		MaxNumberOfMessages: int64(maxNumberOfmessages),
+		VisibilityTimeout:   int64(visibilityTimeout),
+		WaitTimeSeconds:     int64(waitTimeSeconds),
 	}, nil
 }
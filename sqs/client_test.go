@@ -1,12 +1,15 @@
 package sqs_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/stretchr/testify/assert"
 	"github.com/vidsy/awswrappers/sqs"
 )
 
@@ -18,10 +21,37 @@ type (
 		mockReceiveMessage func(*sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error)
 		mockSendMessage    func(*sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error)
 		mockDeleteMessge   func(*sqsLib.DeleteMessageInput) (*sqsLib.DeleteMessageOutput, error)
+
+		mockSendMessageBatch   func(*sqsLib.SendMessageBatchInput) (*sqsLib.SendMessageBatchOutput, error)
+		mockDeleteMessageBatch func(*sqsLib.DeleteMessageBatchInput) (*sqsLib.DeleteMessageBatchOutput, error)
+
+		mockChangeMessageVisibility func(*sqsLib.ChangeMessageVisibilityInput) (*sqsLib.ChangeMessageVisibilityOutput, error)
+		mockGetQueueURL             func(*sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error)
+
+		mockCreateQueue        func(*sqsLib.CreateQueueInput) (*sqsLib.CreateQueueOutput, error)
+		mockGetQueueAttributes func(*sqsLib.GetQueueAttributesInput) (*sqsLib.GetQueueAttributesOutput, error)
+		mockListQueues         func(*sqsLib.ListQueuesInput) (*sqsLib.ListQueuesOutput, error)
+		mockSetQueueAttributes func(*sqsLib.SetQueueAttributesInput) (*sqsLib.SetQueueAttributesOutput, error)
 	}
 )
 
-func (smc MockSDKClient) SendMessage(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+func (smc MockSDKClient) ListQueuesWithContext(ctx aws.Context, input *sqsLib.ListQueuesInput, opts ...request.Option) (*sqsLib.ListQueuesOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if smc.mockListQueues != nil {
+		return smc.mockListQueues(input)
+	}
+
+	return nil, nil
+}
+
+func (smc MockSDKClient) SendMessageWithContext(ctx aws.Context, input *sqsLib.SendMessageInput, opts ...request.Option) (*sqsLib.SendMessageOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	if smc.mockSendMessage != nil {
 		return smc.mockSendMessage(input)
 	}
@@ -29,7 +59,11 @@ func (smc MockSDKClient) SendMessage(input *sqsLib.SendMessageInput) (*sqsLib.Se
 	return nil, nil
 }
 
-func (smc MockSDKClient) ReceiveMessage(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+func (smc MockSDKClient) ReceiveMessageWithContext(ctx aws.Context, input *sqsLib.ReceiveMessageInput, opts ...request.Option) (*sqsLib.ReceiveMessageOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	if smc.mockReceiveMessage != nil {
 		return smc.mockReceiveMessage(input)
 	}
@@ -37,7 +71,11 @@ func (smc MockSDKClient) ReceiveMessage(input *sqsLib.ReceiveMessageInput) (*sqs
 	return nil, nil
 }
 
-func (smc MockSDKClient) DeleteMessage(input *sqsLib.DeleteMessageInput) (*sqsLib.DeleteMessageOutput, error) {
+func (smc MockSDKClient) DeleteMessageWithContext(ctx aws.Context, input *sqsLib.DeleteMessageInput, opts ...request.Option) (*sqsLib.DeleteMessageOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	if smc.mockDeleteMessge != nil {
 		return smc.mockDeleteMessge(input)
 	}
@@ -45,6 +83,90 @@ func (smc MockSDKClient) DeleteMessage(input *sqsLib.DeleteMessageInput) (*sqsLi
 	return nil, nil
 }
 
+func (smc MockSDKClient) SendMessageBatchWithContext(ctx aws.Context, input *sqsLib.SendMessageBatchInput, opts ...request.Option) (*sqsLib.SendMessageBatchOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if smc.mockSendMessageBatch != nil {
+		return smc.mockSendMessageBatch(input)
+	}
+
+	return nil, nil
+}
+
+func (smc MockSDKClient) DeleteMessageBatchWithContext(ctx aws.Context, input *sqsLib.DeleteMessageBatchInput, opts ...request.Option) (*sqsLib.DeleteMessageBatchOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if smc.mockDeleteMessageBatch != nil {
+		return smc.mockDeleteMessageBatch(input)
+	}
+
+	return nil, nil
+}
+
+func (smc MockSDKClient) ChangeMessageVisibilityWithContext(ctx aws.Context, input *sqsLib.ChangeMessageVisibilityInput, opts ...request.Option) (*sqsLib.ChangeMessageVisibilityOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if smc.mockChangeMessageVisibility != nil {
+		return smc.mockChangeMessageVisibility(input)
+	}
+
+	return nil, nil
+}
+
+func (smc MockSDKClient) GetQueueUrlWithContext(ctx aws.Context, input *sqsLib.GetQueueUrlInput, opts ...request.Option) (*sqsLib.GetQueueUrlOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if smc.mockGetQueueURL != nil {
+		return smc.mockGetQueueURL(input)
+	}
+
+	return nil, nil
+}
+
+func (smc MockSDKClient) CreateQueueWithContext(ctx aws.Context, input *sqsLib.CreateQueueInput, opts ...request.Option) (*sqsLib.CreateQueueOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if smc.mockCreateQueue != nil {
+		return smc.mockCreateQueue(input)
+	}
+
+	return nil, nil
+}
+
+func (smc MockSDKClient) GetQueueAttributesWithContext(ctx aws.Context, input *sqsLib.GetQueueAttributesInput, opts ...request.Option) (*sqsLib.GetQueueAttributesOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if smc.mockGetQueueAttributes != nil {
+		return smc.mockGetQueueAttributes(input)
+	}
+
+	return nil, nil
+}
+
+func (smc MockSDKClient) SetQueueAttributesWithContext(ctx aws.Context, input *sqsLib.SetQueueAttributesInput, opts ...request.Option) (*sqsLib.SetQueueAttributesOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if smc.mockSetQueueAttributes != nil {
+		return smc.mockSetQueueAttributes(input)
+	}
+
+	return nil, nil
+}
+
 func NewTestClient(mockClient *MockSDKClient) *sqs.Client {
 	if mockClient == nil {
 		mockClient = &MockSDKClient{}
@@ -124,6 +246,61 @@ func TestClient(t *testing.T) {
 		})
 	})
 
+	t.Run(".ReceiveMessages", func(t *testing.T) {
+		t.Run("ReturnsAllMessages", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+					return &sqsLib.ReceiveMessageOutput{
+						Messages: GenerateMessages(5),
+					}, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			messages, err := client.ReceiveMessages("foo")
+
+			assert.NoError(t, err)
+			assert.Len(t, messages, 5)
+		})
+
+		t.Run("ReturnsNoMessagesWhenQueueEmpty", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+					return &sqsLib.ReceiveMessageOutput{}, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			messages, err := client.ReceiveMessages("foo")
+
+			assert.NoError(t, err)
+			assert.Empty(t, messages)
+		})
+
+		t.Run("ReturnsErrorOnClientError", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+					return nil, errors.New("Client error")
+				},
+			}
+
+			client := NewTestClient(&mock)
+			_, err := client.ReceiveMessages("foo")
+
+			assert.Error(t, err)
+		})
+
+		t.Run("ReturnsErrorWhenContextCancelled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			client := NewTestClient(nil)
+			_, err := client.ReceiveMessagesWithContext(ctx, "foo")
+
+			assert.Equal(t, context.Canceled, err)
+		})
+	})
+
 	t.Run(".SendNewMessage", func(t *testing.T) {
 		message := []byte("test message")
 
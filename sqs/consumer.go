@@ -0,0 +1,153 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/vidsy/awswrappers"
+)
+
+const (
+	defaultConsumerConcurrency = 1
+)
+
+type (
+	// MessageHandler processes a single message received by a Consumer,
+	// returning an error leaves the message on the queue so it is redelivered
+	// once its visibility timeout expires.
+	MessageHandler func(ctx context.Context, message *sqsLib.Message) error
+
+	// Consumer long polls an SQS queue with a number of concurrent workers,
+	// passing each message to a handler and deleting it once handled. The
+	// handler's context holds the trace context of the message, see
+	// Client.MessageContext.
+	Consumer struct {
+		client       *Client
+		concurrency  int
+		errorHandler func(error)
+		handler      MessageHandler
+		queueName    string
+	}
+
+	// detachedContext keeps the values of its parent but is never
+	// cancelled, so handlers can finish once the Consumer is stopped.
+	detachedContext struct {
+		parent context.Context
+	}
+)
+
+var (
+	consumerReceiveErrorPause = 1 * time.Second
+)
+
+// NewConsumer creates a new Consumer for the given queue, concurrency is the
+// number of workers polling the queue, and errorHandler is called with any
+// receive, handler or delete errors (it can be nil).
+func NewConsumer(client *Client, queueName string, concurrency int, handler MessageHandler, errorHandler func(error)) *Consumer {
+	if concurrency < 1 {
+		concurrency = defaultConsumerConcurrency
+	}
+
+	return &Consumer{
+		client:       client,
+		concurrency:  concurrency,
+		errorHandler: errorHandler,
+		handler:      handler,
+		queueName:    queueName,
+	}
+}
+
+// Start runs the workers and blocks until the context is cancelled, once
+// cancelled no new messages are received but messages already received are
+// handled before Start returns. Handlers get a context with the values of
+// ctx that isn't cancelled with it.
+func (c Consumer) Start(ctx context.Context) {
+	var workersWaitGroup sync.WaitGroup
+	workersWaitGroup.Add(c.concurrency)
+
+	for i := 0; i < c.concurrency; i++ {
+		go c.worker(ctx, &workersWaitGroup)
+	}
+
+	workersWaitGroup.Wait()
+}
+
+func (c Consumer) worker(ctx context.Context, workersWaitGroup *sync.WaitGroup) {
+	defer workersWaitGroup.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		messages, err := c.client.ReceiveMessagesWithContext(ctx, c.queueName)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			c.reportError(err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(consumerReceiveErrorPause):
+			}
+
+			continue
+		}
+
+		for _, message := range messages {
+			c.handleMessage(detachedContext{ctx}, message)
+		}
+	}
+}
+
+func (c Consumer) handleMessage(ctx context.Context, message *sqsLib.Message) {
+	ctx, span := c.client.tracer.StartSpan(c.client.MessageContext(ctx, message), "sqs.HandleMessage")
+	span.SetAttribute("queue", c.queueName)
+	span.SetAttribute("message_id", aws.StringValue(message.MessageId))
+
+	err := c.handler(ctx, message)
+	if err != nil {
+		awswrappers.EndSpan(span, err)
+		c.reportError(err)
+		return
+	}
+
+	// Handled messages are deleted even once the context is cancelled so
+	// they aren't redelivered.
+	err = c.client.DeleteMessage(c.queueName, message.ReceiptHandle)
+	awswrappers.EndSpan(span, err)
+
+	if err != nil {
+		c.reportError(err)
+	}
+}
+
+func (d detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (d detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (d detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
+
+func (c Consumer) reportError(err error) {
+	if c.errorHandler != nil {
+		c.errorHandler(err)
+	}
+}
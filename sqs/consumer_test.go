@@ -0,0 +1,188 @@
+package sqs_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/sqs"
+)
+
+func TestConsumer(t *testing.T) {
+	t.Run(".Start()", func(t *testing.T) {
+		t.Run("DeletesHandledMessages", func(t *testing.T) {
+			var mutex sync.Mutex
+			var deletedHandles []string
+
+			mock := MockSDKClient{
+				mockReceiveMessage: onceReceiveMessage(
+					&sqsLib.Message{ReceiptHandle: aws.String("handle_1")},
+					&sqsLib.Message{ReceiptHandle: aws.String("handle_2")},
+				),
+				mockDeleteMessge: func(input *sqsLib.DeleteMessageInput) (*sqsLib.DeleteMessageOutput, error) {
+					mutex.Lock()
+					defer mutex.Unlock()
+
+					deletedHandles = append(deletedHandles, *input.ReceiptHandle)
+					return &sqsLib.DeleteMessageOutput{}, nil
+				},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			handled := 0
+
+			consumer := sqs.NewConsumer(NewTestClient(&mock), "foo", 1, func(ctx context.Context, message *sqsLib.Message) error {
+				handled++
+				if handled == 2 {
+					cancel()
+				}
+
+				return nil
+			}, nil)
+
+			runConsumer(ctx, t, consumer)
+
+			assert.Equal(t, []string{"handle_1", "handle_2"}, deletedHandles)
+		})
+
+		t.Run("LeavesMessageOnHandlerError", func(t *testing.T) {
+			deleteCalled := false
+			var handlerErrors []error
+
+			mock := MockSDKClient{
+				mockReceiveMessage: onceReceiveMessage(
+					&sqsLib.Message{ReceiptHandle: aws.String("handle_1")},
+				),
+				mockDeleteMessge: func(input *sqsLib.DeleteMessageInput) (*sqsLib.DeleteMessageOutput, error) {
+					deleteCalled = true
+					return &sqsLib.DeleteMessageOutput{}, nil
+				},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+
+			consumer := sqs.NewConsumer(NewTestClient(&mock), "foo", 1, func(ctx context.Context, message *sqsLib.Message) error {
+				cancel()
+				return errors.New("Handler error")
+			}, func(err error) {
+				handlerErrors = append(handlerErrors, err)
+			})
+
+			runConsumer(ctx, t, consumer)
+
+			assert.False(t, deleteCalled)
+			assert.Len(t, handlerErrors, 1)
+		})
+
+		t.Run("DrainsInFlightMessagesOnCancel", func(t *testing.T) {
+			handled := 0
+
+			mock := MockSDKClient{
+				mockReceiveMessage: onceReceiveMessage(GenerateMessages(3)...),
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+
+			consumer := sqs.NewConsumer(NewTestClient(&mock), "foo", 1, func(ctx context.Context, message *sqsLib.Message) error {
+				cancel()
+
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+
+				handled++
+				return nil
+			}, nil)
+
+			runConsumer(ctx, t, consumer)
+
+			assert.Equal(t, 3, handled)
+		})
+
+		t.Run("KeepsContextValuesForHandler", func(t *testing.T) {
+			type contextKey struct{}
+			var value interface{}
+
+			mock := MockSDKClient{
+				mockReceiveMessage: onceReceiveMessage(GenerateMessages(1)...),
+			}
+
+			ctx, cancel := context.WithCancel(context.WithValue(context.Background(), contextKey{}, "bar"))
+
+			consumer := sqs.NewConsumer(NewTestClient(&mock), "foo", 1, func(ctx context.Context, message *sqsLib.Message) error {
+				cancel()
+				value = ctx.Value(contextKey{})
+				return nil
+			}, nil)
+
+			runConsumer(ctx, t, consumer)
+
+			assert.Equal(t, "bar", value)
+		})
+
+		t.Run("RunsConcurrentWorkers", func(t *testing.T) {
+			var mutex sync.Mutex
+			receiveCalls := 0
+			allWorkersPolling := make(chan struct{})
+
+			mock := MockSDKClient{
+				mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+					mutex.Lock()
+					receiveCalls++
+					if receiveCalls == 3 {
+						close(allWorkersPolling)
+					}
+					mutex.Unlock()
+
+					<-allWorkersPolling
+					return &sqsLib.ReceiveMessageOutput{}, nil
+				},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			consumer := sqs.NewConsumer(NewTestClient(&mock), "foo", 3, nil, nil)
+
+			go func() {
+				<-allWorkersPolling
+				cancel()
+			}()
+
+			runConsumer(ctx, t, consumer)
+
+			assert.True(t, receiveCalls >= 3)
+		})
+	})
+}
+
+func onceReceiveMessage(messages ...*sqsLib.Message) func(*sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+	var once sync.Once
+
+	return func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+		output := &sqsLib.ReceiveMessageOutput{}
+		once.Do(func() {
+			output.Messages = messages
+		})
+
+		return output, nil
+	}
+}
+
+func runConsumer(ctx context.Context, t *testing.T, consumer *sqs.Consumer) {
+	done := make(chan struct{})
+
+	go func() {
+		consumer.Start(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected consumer to stop after context was cancelled")
+	}
+}
@@ -0,0 +1,252 @@
+package sqs
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+	"github.com/vidsy/awswrappers"
+)
+
+const (
+	peekVisibilityTimeout = 30
+)
+
+type (
+	// MessageFilter decides whether a message on a dead letter queue should
+	// be redriven to its source queue.
+	MessageFilter func(message *sqsLib.Message) bool
+
+	// RedriveReport contains the number of messages moved back to the source
+	// queue and the number left on the dead letter queue by the filter.
+	RedriveReport struct {
+		Moved   int
+		Skipped int
+	}
+)
+
+// PeekMessages returns up to maxMessages distinct messages from the given
+// queue without deleting them, the messages are made visible again before
+// returning so they remain available to other consumers. Peeking stops early
+// once a receive only returns messages that were already peeked.
+func (s Client) PeekMessages(queueName string, maxMessages int) ([]*sqsLib.Message, error) {
+	return s.PeekMessagesWithContext(context.Background(), queueName, maxMessages)
+}
+
+// PeekMessagesWithContext is PeekMessages with a context that can cancel the
+// requests.
+func (s Client) PeekMessagesWithContext(ctx context.Context, queueName string, maxMessages int) ([]*sqsLib.Message, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "sqs.PeekMessages")
+	span.SetAttribute("queue", queueName)
+
+	messages, err := s.peekMessages(ctx, queueName, maxMessages)
+	span.SetAttribute("messages", len(messages))
+	awswrappers.EndSpan(span, err)
+
+	return messages, err
+}
+
+func (s Client) peekMessages(ctx context.Context, queueName string, maxMessages int) ([]*sqsLib.Message, error) {
+	var messages []*sqsLib.Message
+	messageIndices := make(map[string]int)
+
+	for len(messages) < maxMessages {
+		batchSize := maxMessages - len(messages)
+		if batchSize > batchMaxEntries {
+			batchSize = batchMaxEntries
+		}
+
+		params, err := s.deadLetterReceiveParams(ctx, queueName, int64(batchSize))
+		if err != nil {
+			s.releaseMessages(ctx, queueName, messages)
+			return nil, err
+		}
+
+		resp, err := s.SQSAPI.ReceiveMessageWithContext(ctx, params)
+		if err != nil {
+			s.releaseMessages(ctx, queueName, messages)
+			return nil, err
+		}
+
+		unseenMessages := 0
+
+		for _, message := range resp.Messages {
+			messageID := aws.StringValue(message.MessageId)
+
+			// A repeated message is kept with its latest receipt handle so
+			// that it can be released.
+			if index, ok := messageIndices[messageID]; ok {
+				messages[index] = message
+				continue
+			}
+
+			messageIndices[messageID] = len(messages)
+			messages = append(messages, message)
+			unseenMessages++
+		}
+
+		if unseenMessages == 0 {
+			break
+		}
+	}
+
+	err := s.releaseMessages(ctx, queueName, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// RedriveMessages moves messages from a dead letter queue back to the source
+// queue, preserving the body and message attributes and for FIFO queues the
+// group and deduplication IDs. Only messages the filter returns true for are
+// moved, a nil filter moves every message.
+func (s Client) RedriveMessages(deadLetterQueueName string, sourceQueueName string, filter MessageFilter) (*RedriveReport, error) {
+	return s.RedriveMessagesWithContext(context.Background(), deadLetterQueueName, sourceQueueName, filter)
+}
+
+// RedriveMessagesWithContext is RedriveMessages with a context that can
+// cancel the requests, messages moved before cancellation are included in the
+// report.
+func (s Client) RedriveMessagesWithContext(ctx context.Context, deadLetterQueueName string, sourceQueueName string, filter MessageFilter) (*RedriveReport, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "sqs.RedriveMessages")
+	span.SetAttribute("dead_letter_queue", deadLetterQueueName)
+	span.SetAttribute("queue", sourceQueueName)
+
+	report, err := s.redriveMessages(ctx, deadLetterQueueName, sourceQueueName, filter)
+	span.SetAttribute("moved", report.Moved)
+	span.SetAttribute("skipped", report.Skipped)
+	awswrappers.EndSpan(span, err)
+
+	return report, err
+}
+
+func (s Client) redriveMessages(ctx context.Context, deadLetterQueueName string, sourceQueueName string, filter MessageFilter) (*RedriveReport, error) {
+	report := &RedriveReport{}
+	skippedMessages := make(map[string]*sqsLib.Message)
+
+	for {
+		params, err := s.deadLetterReceiveParams(ctx, deadLetterQueueName, batchMaxEntries)
+		if err != nil {
+			s.releaseMessages(ctx, deadLetterQueueName, messagesFromMap(skippedMessages))
+			return report, err
+		}
+
+		resp, err := s.SQSAPI.ReceiveMessageWithContext(ctx, params)
+		if err != nil {
+			s.releaseMessages(ctx, deadLetterQueueName, messagesFromMap(skippedMessages))
+			return report, err
+		}
+
+		unseenMessages := 0
+
+		for _, message := range resp.Messages {
+			messageID := aws.StringValue(message.MessageId)
+
+			if _, ok := skippedMessages[messageID]; ok {
+				skippedMessages[messageID] = message
+				continue
+			}
+
+			unseenMessages++
+
+			if filter != nil && !filter(message) {
+				skippedMessages[messageID] = message
+				continue
+			}
+
+			err := s.redriveMessage(ctx, deadLetterQueueName, sourceQueueName, message)
+			if err != nil {
+				s.releaseMessages(ctx, deadLetterQueueName, messagesFromMap(skippedMessages))
+				return report, err
+			}
+
+			report.Moved++
+		}
+
+		if unseenMessages == 0 {
+			break
+		}
+	}
+
+	report.Skipped = len(skippedMessages)
+
+	err := s.releaseMessages(ctx, deadLetterQueueName, messagesFromMap(skippedMessages))
+	if err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+func (s Client) redriveMessage(ctx context.Context, deadLetterQueueName string, sourceQueueName string, message *sqsLib.Message) error {
+	queueURL, err := s.queueURL(ctx, sourceQueueName)
+	if err != nil {
+		return err
+	}
+
+	params := &sqsLib.SendMessageInput{
+		MessageBody: message.Body,
+		QueueUrl:    aws.String(queueURL),
+	}
+
+	if len(message.MessageAttributes) > 0 {
+		params.MessageAttributes = message.MessageAttributes
+	}
+
+	if strings.HasSuffix(sourceQueueName, fifoQueueSuffix) {
+		params.MessageGroupId = message.Attributes[sqsLib.MessageSystemAttributeNameMessageGroupId]
+		params.MessageDeduplicationId = message.Attributes[sqsLib.MessageSystemAttributeNameMessageDeduplicationId]
+	}
+
+	_, err = s.SQSAPI.SendMessageWithContext(ctx, params)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"Problem redriving message '%s' to queue '%s'",
+			aws.StringValue(message.MessageId),
+			sourceQueueName,
+		)
+	}
+
+	return s.DeleteMessageWithContext(ctx, deadLetterQueueName, message.ReceiptHandle)
+}
+
+func (s Client) releaseMessages(ctx context.Context, queueName string, messages []*sqsLib.Message) error {
+	for _, message := range messages {
+		err := s.ChangeVisibilityTimeoutWithContext(ctx, queueName, message.ReceiptHandle, 0)
+		if err != nil {
+			return errors.Wrapf(
+				err,
+				"Problem making message '%s' visible again",
+				aws.StringValue(message.MessageId),
+			)
+		}
+	}
+
+	return nil
+}
+
+func messagesFromMap(messagesByID map[string]*sqsLib.Message) []*sqsLib.Message {
+	messages := make([]*sqsLib.Message, 0, len(messagesByID))
+	for _, message := range messagesByID {
+		messages = append(messages, message)
+	}
+
+	return messages
+}
+
+func (s Client) deadLetterReceiveParams(ctx context.Context, queueName string, maxNumberOfMessages int64) (*sqsLib.ReceiveMessageInput, error) {
+	params, err := s.receiveMessageParams(ctx, queueName)
+	if err != nil {
+		return nil, err
+	}
+
+	params.MaxNumberOfMessages = aws.Int64(maxNumberOfMessages)
+	params.VisibilityTimeout = aws.Int64(peekVisibilityTimeout)
+
+	return params, nil
+}
@@ -0,0 +1,208 @@
+package sqs_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetterQueue(t *testing.T) {
+	t.Run(".PeekMessages()", func(t *testing.T) {
+		t.Run("ReturnsMessagesAndMakesThemVisible", func(t *testing.T) {
+			var releasedHandles []string
+
+			mock := MockSDKClient{
+				mockReceiveMessage: onceReceiveMessage(deadLetterMessages("1", "2")...),
+				mockChangeMessageVisibility: func(input *sqsLib.ChangeMessageVisibilityInput) (*sqsLib.ChangeMessageVisibilityOutput, error) {
+					assert.Equal(t, int64(0), *input.VisibilityTimeout)
+					releasedHandles = append(releasedHandles, *input.ReceiptHandle)
+
+					return &sqsLib.ChangeMessageVisibilityOutput{}, nil
+				},
+				mockDeleteMessge: func(input *sqsLib.DeleteMessageInput) (*sqsLib.DeleteMessageOutput, error) {
+					t.Fatal("Expected peeked messages not to be deleted")
+					return nil, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			messages, err := client.PeekMessages("foo-dlq", 5)
+
+			assert.NoError(t, err)
+			assert.Len(t, messages, 2)
+			assert.Equal(t, []string{"handle_1", "handle_2"}, releasedHandles)
+		})
+
+		t.Run("StopsWhenMessagesRepeat", func(t *testing.T) {
+			var releasedHandles []string
+			receiveCalls := 0
+
+			mock := MockSDKClient{
+				mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+					receiveCalls++
+
+					messages := deadLetterMessages("1", "2")
+					for _, message := range messages {
+						message.ReceiptHandle = aws.String(fmt.Sprintf("%s_%d", *message.ReceiptHandle, receiveCalls))
+					}
+
+					return &sqsLib.ReceiveMessageOutput{Messages: messages}, nil
+				},
+				mockChangeMessageVisibility: func(input *sqsLib.ChangeMessageVisibilityInput) (*sqsLib.ChangeMessageVisibilityOutput, error) {
+					releasedHandles = append(releasedHandles, *input.ReceiptHandle)
+					return &sqsLib.ChangeMessageVisibilityOutput{}, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			messages, err := client.PeekMessages("foo-dlq", 5)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 2, receiveCalls)
+			assert.Len(t, messages, 2)
+			assert.Equal(t, []string{"handle_1_2", "handle_2_2"}, releasedHandles)
+		})
+
+		t.Run("ReturnsErrorOnClientError", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+					return nil, errors.New("Client error")
+				},
+			}
+
+			client := NewTestClient(&mock)
+			_, err := client.PeekMessages("foo-dlq", 5)
+
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run(".RedriveMessages()", func(t *testing.T) {
+		t.Run("MovesMessagesToSourceQueue", func(t *testing.T) {
+			var sentInputs []*sqsLib.SendMessageInput
+			var deletedHandles []string
+
+			mock := MockSDKClient{
+				mockReceiveMessage: onceReceiveMessage(deadLetterMessages("1", "2")...),
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					sentInputs = append(sentInputs, input)
+					return &sqsLib.SendMessageOutput{MessageId: aws.String("new")}, nil
+				},
+				mockDeleteMessge: func(input *sqsLib.DeleteMessageInput) (*sqsLib.DeleteMessageOutput, error) {
+					deletedHandles = append(deletedHandles, *input.ReceiptHandle)
+					return &sqsLib.DeleteMessageOutput{}, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			report, err := client.RedriveMessages("foo-dlq", "foo", nil)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 2, report.Moved)
+			assert.Equal(t, []string{"handle_1", "handle_2"}, deletedHandles)
+
+			if assert.Len(t, sentInputs, 2) {
+				assert.Equal(t, "http://www.test.com/foo", *sentInputs[0].QueueUrl)
+				assert.Equal(t, "body_1", *sentInputs[0].MessageBody)
+				assert.Equal(t, "bar", *sentInputs[0].MessageAttributes["foo"].StringValue)
+				assert.Nil(t, sentInputs[0].MessageGroupId)
+			}
+		})
+
+		t.Run("PreservesFIFOAttributes", func(t *testing.T) {
+			var sentInput *sqsLib.SendMessageInput
+
+			mock := MockSDKClient{
+				mockReceiveMessage: onceReceiveMessage(deadLetterMessages("1")...),
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					sentInput = input
+					return &sqsLib.SendMessageOutput{MessageId: aws.String("new")}, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			_, err := client.RedriveMessages("foo-dlq.fifo", "foo.fifo", nil)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "group_1", *sentInput.MessageGroupId)
+			assert.Equal(t, "dedup_1", *sentInput.MessageDeduplicationId)
+		})
+
+		t.Run("LeavesFilteredMessagesOnDeadLetterQueue", func(t *testing.T) {
+			var sentBodies []string
+			var releasedHandles []string
+
+			mock := MockSDKClient{
+				mockReceiveMessage: onceReceiveMessage(deadLetterMessages("1", "2")...),
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					sentBodies = append(sentBodies, *input.MessageBody)
+					return &sqsLib.SendMessageOutput{MessageId: aws.String("new")}, nil
+				},
+				mockChangeMessageVisibility: func(input *sqsLib.ChangeMessageVisibilityInput) (*sqsLib.ChangeMessageVisibilityOutput, error) {
+					releasedHandles = append(releasedHandles, *input.ReceiptHandle)
+					return &sqsLib.ChangeMessageVisibilityOutput{}, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			report, err := client.RedriveMessages("foo-dlq", "foo", func(message *sqsLib.Message) bool {
+				return *message.MessageId == "2"
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Moved)
+			assert.Equal(t, 1, report.Skipped)
+			assert.Equal(t, []string{"body_2"}, sentBodies)
+			assert.Equal(t, []string{"handle_1"}, releasedHandles)
+		})
+
+		t.Run("ReturnsErrorWhenSendFails", func(t *testing.T) {
+			deleteCalled := false
+
+			mock := MockSDKClient{
+				mockReceiveMessage: onceReceiveMessage(deadLetterMessages("1")...),
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					return nil, errors.New("Client error")
+				},
+				mockDeleteMessge: func(input *sqsLib.DeleteMessageInput) (*sqsLib.DeleteMessageOutput, error) {
+					deleteCalled = true
+					return &sqsLib.DeleteMessageOutput{}, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			report, err := client.RedriveMessages("foo-dlq", "foo", nil)
+
+			assert.Error(t, err)
+			assert.Equal(t, 0, report.Moved)
+			assert.False(t, deleteCalled)
+		})
+	})
+}
+
+func deadLetterMessages(ids ...string) []*sqsLib.Message {
+	messages := make([]*sqsLib.Message, 0, len(ids))
+	for _, id := range ids {
+		messages = append(messages, &sqsLib.Message{
+			Attributes: map[string]*string{
+				sqsLib.MessageSystemAttributeNameMessageGroupId:         aws.String("group_" + id),
+				sqsLib.MessageSystemAttributeNameMessageDeduplicationId: aws.String("dedup_" + id),
+			},
+			Body: aws.String("body_" + id),
+			MessageAttributes: map[string]*sqsLib.MessageAttributeValue{
+				"foo": {
+					DataType:    aws.String("String"),
+					StringValue: aws.String("bar"),
+				},
+			},
+			MessageId:     aws.String(id),
+			ReceiptHandle: aws.String("handle_" + id),
+		})
+	}
+
+	return messages
+}
@@ -0,0 +1,169 @@
+package sqs
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+	"github.com/vidsy/awswrappers"
+)
+
+const (
+	// DelayedUntilAttribute is the message attribute set on messages sent by
+	// SendDelayed with a delay longer than MaxDelay, it contains the time the
+	// message is due in milliseconds since the epoch.
+	DelayedUntilAttribute = "SQSDelayedUntil"
+
+	// MaxDelay is the longest delay SQS supports for a single message.
+	MaxDelay = 900 * time.Second
+)
+
+// SendDelayed sends an SQS message that isn't received until the delay has
+// passed. Delays up to MaxDelay use DelaySeconds, longer delays are sent in
+// hops of MaxDelay with a DelayedUntilAttribute, and ReceiveMessages
+// re-enqueues the message with the remaining delay until it is due, so the
+// returned message ID is only the ID of the first hop. FIFO queues don't
+// support per message delays and return an error.
+func (s Client) SendDelayed(queueName string, body []byte, delay time.Duration) (string, error) {
+	return s.SendDelayedWithContext(context.Background(), queueName, body, delay)
+}
+
+// SendDelayedWithContext is SendDelayed with a context that can cancel the
+// request.
+func (s Client) SendDelayedWithContext(ctx context.Context, queueName string, body []byte, delay time.Duration) (string, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "sqs.SendDelayed")
+	span.SetAttribute("queue", queueName)
+	span.SetAttribute("delay", delay.String())
+
+	messageID, err := s.sendDelayed(ctx, queueName, body, delay)
+	if err == nil {
+		span.SetAttribute("message_id", messageID)
+	}
+
+	awswrappers.EndSpan(span, err)
+
+	return messageID, err
+}
+
+func (s Client) sendDelayed(ctx context.Context, queueName string, body []byte, delay time.Duration) (string, error) {
+	if strings.HasSuffix(queueName, fifoQueueSuffix) {
+		return "", errors.Errorf(
+			"Queue '%s' is a FIFO queue, messages sent to FIFO queues can't be delayed",
+			queueName,
+		)
+	}
+
+	if delay < 0 {
+		return "", errors.Errorf("Delay can't be negative, got '%s'", delay)
+	}
+
+	queueURL, err := s.queueURL(ctx, queueName)
+	if err != nil {
+		return "", err
+	}
+
+	var messageAttributes map[string]*sqsLib.MessageAttributeValue
+
+	if delay > MaxDelay {
+		messageAttributes = map[string]*sqsLib.MessageAttributeValue{
+			DelayedUntilAttribute: {
+				DataType:    aws.String("Number"),
+				StringValue: aws.String(strconv.FormatInt(unixMilliseconds(time.Now().Add(delay)), 10)),
+			},
+		}
+	}
+
+	messageBody, messageAttributes, err := s.offloadPayload(ctx, body, s.injectTraceContext(ctx, messageAttributes))
+	if err != nil {
+		return "", err
+	}
+
+	params := &sqsLib.SendMessageInput{
+		DelaySeconds:      aws.Int64(delaySeconds(delay)),
+		MessageAttributes: messageAttributes,
+		MessageBody:       aws.String(messageBody),
+		QueueUrl:          aws.String(queueURL),
+	}
+
+	resp, err := s.SQSAPI.SendMessageWithContext(ctx, params)
+	if err != nil {
+		s.discardPayload(ctx, messageBody, messageAttributes)
+		return "", err
+	}
+
+	return *resp.MessageId, nil
+}
+
+// deferDelayedMessage re-enqueues the message with its remaining delay if it
+// isn't due yet, returning true when the message was deferred. The body is
+// sent as received so any LargePayloadStore object is kept.
+func (s Client) deferDelayedMessage(ctx context.Context, queueName string, message *sqsLib.Message) (bool, error) {
+	attribute, ok := message.MessageAttributes[DelayedUntilAttribute]
+	if !ok {
+		return false, nil
+	}
+
+	delayedUntil, err := strconv.ParseInt(aws.StringValue(attribute.StringValue), 10, 64)
+	if err != nil {
+		return false, errors.Wrapf(
+			err,
+			"Problem parsing attribute '%s' of message '%s'",
+			DelayedUntilAttribute,
+			aws.StringValue(message.MessageId),
+		)
+	}
+
+	remainingDelay := time.Duration(delayedUntil-unixMilliseconds(time.Now())) * time.Millisecond
+	if remainingDelay < time.Second {
+		return false, nil
+	}
+
+	queueURL, err := s.queueURL(ctx, queueName)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = s.SQSAPI.SendMessageWithContext(ctx, &sqsLib.SendMessageInput{
+		DelaySeconds:      aws.Int64(delaySeconds(remainingDelay)),
+		MessageAttributes: message.MessageAttributes,
+		MessageBody:       message.Body,
+		QueueUrl:          aws.String(queueURL),
+	})
+	if err != nil {
+		return false, errors.Wrapf(
+			err,
+			"Unable to re-enqueue delayed message '%s'",
+			aws.StringValue(message.MessageId),
+		)
+	}
+
+	_, err = s.SQSAPI.DeleteMessageWithContext(ctx, &sqsLib.DeleteMessageInput{
+		QueueUrl:      aws.String(queueURL),
+		ReceiptHandle: message.ReceiptHandle,
+	})
+	if err != nil {
+		return false, errors.Wrapf(
+			err,
+			"Unable to delete re-enqueued delayed message '%s'",
+			aws.StringValue(message.MessageId),
+		)
+	}
+
+	return true, nil
+}
+
+func delaySeconds(delay time.Duration) int64 {
+	if delay > MaxDelay {
+		delay = MaxDelay
+	}
+
+	return int64((delay + time.Second - 1) / time.Second)
+}
+
+func unixMilliseconds(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,182 @@
+package sqs_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/sqs"
+)
+
+func delayedMessage(delayedUntil time.Time) *sqsLib.Message {
+	return &sqsLib.Message{
+		Body: aws.String("body"),
+		MessageAttributes: map[string]*sqsLib.MessageAttributeValue{
+			sqs.DelayedUntilAttribute: {
+				DataType:    aws.String("Number"),
+				StringValue: aws.String(strconv.FormatInt(delayedUntil.UnixNano()/int64(time.Millisecond), 10)),
+			},
+		},
+		MessageId:     aws.String("1"),
+		ReceiptHandle: aws.String("handle_1"),
+	}
+}
+
+func TestDelayed(t *testing.T) {
+	t.Run(".SendDelayed()", func(t *testing.T) {
+		t.Run("UsesDelaySecondsForShortDelays", func(t *testing.T) {
+			var sentInput *sqsLib.SendMessageInput
+
+			mock := MockSDKClient{
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					sentInput = input
+					return &sqsLib.SendMessageOutput{MessageId: aws.String("1")}, nil
+				},
+			}
+
+			messageID, err := NewTestClient(&mock).SendDelayed("foo", []byte("body"), 90*time.Second)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "1", messageID)
+			assert.Equal(t, int64(90), *sentInput.DelaySeconds)
+			assert.Nil(t, sentInput.MessageAttributes)
+		})
+
+		t.Run("SetsDelayedUntilForLongDelays", func(t *testing.T) {
+			var sentInput *sqsLib.SendMessageInput
+
+			mock := MockSDKClient{
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					sentInput = input
+					return &sqsLib.SendMessageOutput{MessageId: aws.String("1")}, nil
+				},
+			}
+
+			delayedUntil := time.Now().Add(time.Hour)
+			_, err := NewTestClient(&mock).SendDelayed("foo", []byte("body"), time.Hour)
+			assert.NoError(t, err)
+
+			sentDelayedUntil, err := strconv.ParseInt(*sentInput.MessageAttributes[sqs.DelayedUntilAttribute].StringValue, 10, 64)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(900), *sentInput.DelaySeconds)
+			assert.InDelta(t, delayedUntil.UnixNano()/int64(time.Millisecond), sentDelayedUntil, 1000)
+		})
+
+		t.Run("ReturnsErrorForFIFOQueue", func(t *testing.T) {
+			_, err := NewTestClient(nil).SendDelayed("foo.fifo", []byte("body"), time.Minute)
+			assert.Error(t, err)
+		})
+
+		t.Run("ReturnsErrorForNegativeDelay", func(t *testing.T) {
+			_, err := NewTestClient(nil).SendDelayed("foo", []byte("body"), -time.Minute)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run(".ReceiveMessages()", func(t *testing.T) {
+		t.Run("ReenqueuesMessagesThatAreNotDue", func(t *testing.T) {
+			var sentInput *sqsLib.SendMessageInput
+			var deletedHandle string
+
+			mock := MockSDKClient{
+				mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+					return &sqsLib.ReceiveMessageOutput{
+						Messages: []*sqsLib.Message{
+							delayedMessage(time.Now().Add(20 * time.Minute)),
+						},
+					}, nil
+				},
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					sentInput = input
+					return &sqsLib.SendMessageOutput{MessageId: aws.String("2")}, nil
+				},
+				mockDeleteMessge: func(input *sqsLib.DeleteMessageInput) (*sqsLib.DeleteMessageOutput, error) {
+					deletedHandle = *input.ReceiptHandle
+					return &sqsLib.DeleteMessageOutput{}, nil
+				},
+			}
+
+			messages, err := NewTestClient(&mock).ReceiveMessages("foo")
+
+			assert.NoError(t, err)
+			assert.Empty(t, messages)
+			assert.Equal(t, int64(900), *sentInput.DelaySeconds)
+			assert.Equal(t, "body", *sentInput.MessageBody)
+			assert.Contains(t, sentInput.MessageAttributes, sqs.DelayedUntilAttribute)
+			assert.Equal(t, "handle_1", deletedHandle)
+		})
+
+		t.Run("UsesRemainingDelayForLastHop", func(t *testing.T) {
+			var sentInput *sqsLib.SendMessageInput
+
+			mock := MockSDKClient{
+				mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+					return &sqsLib.ReceiveMessageOutput{
+						Messages: []*sqsLib.Message{
+							delayedMessage(time.Now().Add(2 * time.Minute)),
+						},
+					}, nil
+				},
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					sentInput = input
+					return &sqsLib.SendMessageOutput{MessageId: aws.String("2")}, nil
+				},
+				mockDeleteMessge: func(input *sqsLib.DeleteMessageInput) (*sqsLib.DeleteMessageOutput, error) {
+					return &sqsLib.DeleteMessageOutput{}, nil
+				},
+			}
+
+			_, err := NewTestClient(&mock).ReceiveMessages("foo")
+
+			assert.NoError(t, err)
+			assert.InDelta(t, 120, *sentInput.DelaySeconds, 1)
+		})
+
+		t.Run("ReturnsMessagesThatAreDue", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+					return &sqsLib.ReceiveMessageOutput{
+						Messages: []*sqsLib.Message{
+							delayedMessage(time.Now().Add(-time.Second)),
+						},
+					}, nil
+				},
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					t.Fatal("Expected due message not to be re-enqueued")
+					return nil, nil
+				},
+			}
+
+			messages, err := NewTestClient(&mock).ReceiveMessages("foo")
+
+			assert.NoError(t, err)
+			assert.Len(t, messages, 1)
+		})
+
+		t.Run("SkipsMessagesThatCantBeDeferred", func(t *testing.T) {
+			invalidMessage := delayedMessage(time.Now())
+			invalidMessage.MessageAttributes[sqs.DelayedUntilAttribute].StringValue = aws.String("soon")
+
+			dueMessage := delayedMessage(time.Now().Add(-time.Second))
+			dueMessage.MessageId = aws.String("2")
+
+			mock := MockSDKClient{
+				mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+					return &sqsLib.ReceiveMessageOutput{
+						Messages: []*sqsLib.Message{invalidMessage, dueMessage},
+					}, nil
+				},
+			}
+
+			messages, err := NewTestClient(&mock).ReceiveMessages("foo")
+
+			assert.NoError(t, err)
+			if assert.Len(t, messages, 1) {
+				assert.Equal(t, "2", *messages[0].MessageId)
+			}
+		})
+	})
+}
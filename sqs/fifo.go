@@ -0,0 +1,145 @@
+package sqs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+)
+
+const (
+	fifoQueueSuffix = ".fifo"
+)
+
+type (
+	// GroupIDStrategy decides the message group ID of a message sent by a
+	// FIFOProducer.
+	GroupIDStrategy interface {
+		GroupID(body []byte) string
+	}
+
+	// FixedGroupID sends every message in the same group, so all messages are
+	// delivered in order.
+	FixedGroupID string
+
+	// GroupIDFunc derives the group ID from the message body, for example
+	// using the ID of the entity the message is about.
+	GroupIDFunc func(body []byte) string
+
+	// RoundRobinGroupID spreads messages over a fixed number of groups so
+	// they can be consumed in parallel.
+	RoundRobinGroupID struct {
+		counter *uint64
+		prefix  string
+		shards  uint64
+	}
+
+	// FIFOProducer sends messages to a FIFO queue, deriving the group ID from
+	// a GroupIDStrategy and the deduplication ID from the body.
+	FIFOProducer struct {
+		client                    *Client
+		contentBasedDeduplication bool
+		groupIDStrategy           GroupIDStrategy
+		queueName                 string
+	}
+)
+
+// GroupID returns the fixed group ID.
+func (f FixedGroupID) GroupID(body []byte) string {
+	return string(f)
+}
+
+// GroupID calls the function with the message body.
+func (f GroupIDFunc) GroupID(body []byte) string {
+	return f(body)
+}
+
+// NewRoundRobinGroupID creates a new RoundRobinGroupID using group IDs
+// "<prefix>-0" to "<prefix>-<shards-1>".
+func NewRoundRobinGroupID(prefix string, shards int) RoundRobinGroupID {
+	if shards < 1 {
+		shards = 1
+	}
+
+	return RoundRobinGroupID{
+		counter: new(uint64),
+		prefix:  prefix,
+		shards:  uint64(shards),
+	}
+}
+
+// GroupID returns the next group ID in the rotation.
+func (r RoundRobinGroupID) GroupID(body []byte) string {
+	next := atomic.AddUint64(r.counter, 1) - 1
+	return fmt.Sprintf("%s-%d", r.prefix, next%r.shards)
+}
+
+// ValidateFIFOQueueName returns an error if the queue name lacks the ".fifo"
+// suffix required of FIFO queues.
+func ValidateFIFOQueueName(queueName string) error {
+	if !strings.HasSuffix(queueName, fifoQueueSuffix) {
+		return errors.Errorf(
+			"Queue '%s' is not a FIFO queue, FIFO queue names must end with '%s'",
+			queueName,
+			fifoQueueSuffix,
+		)
+	}
+
+	return nil
+}
+
+// ContentDeduplicationID returns the hex encoded SHA-256 of the body for use
+// as a message deduplication ID.
+func ContentDeduplicationID(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// NewFIFOProducer creates a new FIFOProducer for the given queue, when
+// contentBasedDeduplication is true the queue is expected to have content
+// based deduplication enabled and no deduplication ID is sent, otherwise
+// the SHA-256 of the body is used.
+func NewFIFOProducer(client *Client, queueName string, groupIDStrategy GroupIDStrategy, contentBasedDeduplication bool) (*FIFOProducer, error) {
+	err := ValidateFIFOQueueName(queueName)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupIDStrategy == nil {
+		return nil, errors.New("A GroupIDStrategy is required to send FIFO messages")
+	}
+
+	return &FIFOProducer{
+		client:                    client,
+		contentBasedDeduplication: contentBasedDeduplication,
+		groupIDStrategy:           groupIDStrategy,
+		queueName:                 queueName,
+	}, nil
+}
+
+// Send sends the body to the FIFO queue and returns the message ID.
+func (p FIFOProducer) Send(body []byte, messageAttributes map[string]*sqsLib.MessageAttributeValue) (string, error) {
+	return p.SendWithContext(context.Background(), body, messageAttributes)
+}
+
+// SendWithContext is Send with a context that can cancel the request.
+func (p FIFOProducer) SendWithContext(ctx context.Context, body []byte, messageAttributes map[string]*sqsLib.MessageAttributeValue) (string, error) {
+	deduplicationID := ""
+	if !p.contentBasedDeduplication {
+		deduplicationID = ContentDeduplicationID(body)
+	}
+
+	return p.client.SendNewFIFOMessageWithContext(
+		ctx,
+		p.queueName,
+		body,
+		deduplicationID,
+		p.groupIDStrategy.GroupID(body),
+		messageAttributes,
+	)
+}
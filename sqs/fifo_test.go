@@ -0,0 +1,131 @@
+package sqs_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/sqs"
+)
+
+func TestFIFO(t *testing.T) {
+	t.Run(".SendNewFIFOMessage()", func(t *testing.T) {
+		t.Run("ReturnsErrorForNonFIFOQueue", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					t.Fatal("Expected message not to be sent")
+					return nil, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			_, err := client.SendNewFIFOMessage("foo", []byte("body"), "dedup", "group", nil)
+
+			assert.Error(t, err)
+		})
+
+		t.Run("OmitsEmptyDeduplicationID", func(t *testing.T) {
+			var sentInput *sqsLib.SendMessageInput
+
+			mock := MockSDKClient{
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					sentInput = input
+					return &sqsLib.SendMessageOutput{MessageId: aws.String("1")}, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			_, err := client.SendNewFIFOMessage("foo.fifo", []byte("body"), "", "group", nil)
+
+			assert.NoError(t, err)
+			assert.Nil(t, sentInput.MessageDeduplicationId)
+			assert.Equal(t, "group", *sentInput.MessageGroupId)
+		})
+	})
+
+	t.Run(".NewFIFOProducer()", func(t *testing.T) {
+		t.Run("ReturnsErrorForNonFIFOQueue", func(t *testing.T) {
+			_, err := sqs.NewFIFOProducer(NewTestClient(nil), "foo", sqs.FixedGroupID("group"), false)
+			assert.Error(t, err)
+		})
+
+		t.Run("ReturnsErrorWithoutGroupIDStrategy", func(t *testing.T) {
+			_, err := sqs.NewFIFOProducer(NewTestClient(nil), "foo.fifo", nil, false)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run(".Send()", func(t *testing.T) {
+		body := []byte("body")
+
+		t.Run("DerivesDeduplicationIDFromBody", func(t *testing.T) {
+			var sentInput *sqsLib.SendMessageInput
+
+			mock := MockSDKClient{
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					sentInput = input
+					return &sqsLib.SendMessageOutput{MessageId: aws.String("1")}, nil
+				},
+			}
+
+			producer, err := sqs.NewFIFOProducer(NewTestClient(&mock), "foo.fifo", sqs.FixedGroupID("group"), false)
+			assert.NoError(t, err)
+
+			messageID, err := producer.Send(body, nil)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "1", messageID)
+			assert.Equal(t, sqs.ContentDeduplicationID(body), *sentInput.MessageDeduplicationId)
+			assert.Equal(t, "group", *sentInput.MessageGroupId)
+		})
+
+		t.Run("OmitsDeduplicationIDForContentBasedDeduplication", func(t *testing.T) {
+			var sentInput *sqsLib.SendMessageInput
+
+			mock := MockSDKClient{
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					sentInput = input
+					return &sqsLib.SendMessageOutput{MessageId: aws.String("1")}, nil
+				},
+			}
+
+			producer, err := sqs.NewFIFOProducer(NewTestClient(&mock), "foo.fifo", sqs.FixedGroupID("group"), true)
+			assert.NoError(t, err)
+
+			_, err = producer.Send(body, nil)
+
+			assert.NoError(t, err)
+			assert.Nil(t, sentInput.MessageDeduplicationId)
+		})
+	})
+
+	t.Run("GroupIDStrategies", func(t *testing.T) {
+		t.Run("GroupIDFuncUsesBody", func(t *testing.T) {
+			strategy := sqs.GroupIDFunc(func(body []byte) string {
+				return "user_" + string(body)
+			})
+
+			assert.Equal(t, "user_123", strategy.GroupID([]byte("123")))
+		})
+
+		t.Run("RoundRobinCyclesShards", func(t *testing.T) {
+			strategy := sqs.NewRoundRobinGroupID("jobs", 3)
+
+			var groupIDs []string
+			for i := 0; i < 4; i++ {
+				groupIDs = append(groupIDs, strategy.GroupID(nil))
+			}
+
+			assert.Equal(t, []string{"jobs-0", "jobs-1", "jobs-2", "jobs-0"}, groupIDs)
+		})
+	})
+
+	t.Run(".ContentDeduplicationID()", func(t *testing.T) {
+		assert.Equal(
+			t,
+			"230d8358dc8e8890b4c58deeb62912ee2f20357ae92a5cc861b98e68fe31acb5",
+			sqs.ContentDeduplicationID([]byte("body")),
+		)
+	})
+}
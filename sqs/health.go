@@ -0,0 +1,41 @@
+package sqs
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+)
+
+// HealthCheck checks SQS can be reached, fetching the attributes of the
+// queues set with WithHealthCheckQueues or listing queues when none are set.
+func (s Client) HealthCheck(ctx context.Context) error {
+	if len(s.healthCheckQueues) == 0 {
+		_, err := s.SQSAPI.ListQueuesWithContext(ctx, &sqsLib.ListQueuesInput{})
+		if err != nil {
+			return errors.Wrap(err, "Unable to list SQS queues")
+		}
+
+		return nil
+	}
+
+	for _, queueName := range s.healthCheckQueues {
+		queueURL, err := s.queueURL(ctx, queueName)
+		if err != nil {
+			return err
+		}
+
+		params := &sqsLib.GetQueueAttributesInput{
+			AttributeNames: []*string{aws.String(sqsLib.QueueAttributeNameQueueArn)},
+			QueueUrl:       aws.String(queueURL),
+		}
+
+		_, err = s.SQSAPI.GetQueueAttributesWithContext(ctx, params)
+		if err != nil {
+			return errors.Wrapf(err, "Unable to get attributes of SQS queue '%s'", queueName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package sqs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/sqs"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run(".HealthCheck()", func(t *testing.T) {
+		t.Run("ListsQueuesWithoutHealthCheckQueues", func(t *testing.T) {
+			listed := false
+
+			mock := MockSDKClient{
+				mockListQueues: func(input *sqsLib.ListQueuesInput) (*sqsLib.ListQueuesOutput, error) {
+					listed = true
+					return &sqsLib.ListQueuesOutput{}, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			err := client.HealthCheck(context.Background())
+
+			assert.NoError(t, err)
+			assert.True(t, listed)
+		})
+
+		t.Run("GetsAttributesOfHealthCheckQueues", func(t *testing.T) {
+			var queueURLs []string
+
+			mock := MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					return &sqsLib.GetQueueUrlOutput{
+						QueueUrl: aws.String("http://sqs/" + *input.QueueName),
+					}, nil
+				},
+				mockGetQueueAttributes: func(input *sqsLib.GetQueueAttributesInput) (*sqsLib.GetQueueAttributesOutput, error) {
+					queueURLs = append(queueURLs, *input.QueueUrl)
+					return &sqsLib.GetQueueAttributesOutput{}, nil
+				},
+			}
+
+			client := sqs.NewClientWithOptions(
+				sqs.WithAPI(&mock),
+				sqs.WithClientConfig(&sqs.ClientConfig{}),
+				sqs.WithHealthCheckQueues("foo", "bar"),
+			)
+			err := client.HealthCheck(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"http://sqs/foo", "http://sqs/bar"}, queueURLs)
+		})
+
+		t.Run("ReturnsErrorForUnavailableQueue", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					return &sqsLib.GetQueueUrlOutput{
+						QueueUrl: aws.String("http://sqs/" + *input.QueueName),
+					}, nil
+				},
+				mockGetQueueAttributes: func(input *sqsLib.GetQueueAttributesInput) (*sqsLib.GetQueueAttributesOutput, error) {
+					return nil, errors.New("Access denied")
+				},
+			}
+
+			client := sqs.NewClientWithOptions(
+				sqs.WithAPI(&mock),
+				sqs.WithClientConfig(&sqs.ClientConfig{}),
+				sqs.WithHealthCheckQueues("foo"),
+			)
+			err := client.HealthCheck(context.Background())
+
+			assert.EqualError(t, err, "Unable to get attributes of SQS queue 'foo': Access denied")
+		})
+	})
+}
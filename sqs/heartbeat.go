@@ -0,0 +1,123 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+)
+
+type (
+	// Heartbeat periodically extends the visibility timeout of a received
+	// message so it isn't redelivered while a long running job is processing
+	// it.
+	Heartbeat struct {
+		client        *Client
+		done          chan struct{}
+		errorHandler  func(error)
+		extension     int64
+		interval      time.Duration
+		mutex         sync.Mutex
+		queueName     string
+		receiptHandle *string
+		started       bool
+		stop          chan struct{}
+		stopOnce      sync.Once
+	}
+)
+
+// NewHeartbeat creates a new Heartbeat for the given message, each beat
+// extends the visibility timeout by the given extension, which must be at
+// least a second. The interval must be shorter than the extension so the
+// message stays invisible between beats, an interval of 0 beats at half of
+// the extension. errorHandler is called when an extension fails (it can be
+// nil).
+func NewHeartbeat(client *Client, queueName string, message *sqsLib.Message, extension time.Duration, interval time.Duration, errorHandler func(error)) (*Heartbeat, error) {
+	if extension < time.Second {
+		return nil, errors.Errorf(
+			"Unable to create heartbeat, extension of %s is shorter than a second",
+			extension,
+		)
+	}
+
+	if interval < 0 || interval >= extension {
+		return nil, errors.Errorf(
+			"Unable to create heartbeat, interval of %s must be positive and shorter than the extension of %s",
+			interval,
+			extension,
+		)
+	}
+
+	if interval == 0 {
+		interval = extension / 2
+	}
+
+	return &Heartbeat{
+		client:        client,
+		done:          make(chan struct{}),
+		errorHandler:  errorHandler,
+		extension:     int64(extension / time.Second),
+		interval:      interval,
+		queueName:     queueName,
+		receiptHandle: message.ReceiptHandle,
+		stop:          make(chan struct{}),
+	}, nil
+}
+
+// Start begins extending the visibility timeout in the background until Stop
+// is called or the context is cancelled, calling it again has no effect.
+func (h *Heartbeat) Start(ctx context.Context) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.started {
+		return
+	}
+
+	h.started = true
+
+	go func() {
+		defer close(h.done)
+
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.stop:
+				return
+			case <-ticker.C:
+				err := h.client.ChangeVisibilityTimeoutWithContext(
+					ctx,
+					h.queueName,
+					h.receiptHandle,
+					h.extension,
+				)
+				if err != nil && h.errorHandler != nil {
+					h.errorHandler(err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop signals the job has completed and blocks until the heartbeat has
+// stopped extending the visibility timeout, it returns straight away if
+// Start wasn't called.
+func (h *Heartbeat) Stop() {
+	h.stopOnce.Do(func() {
+		close(h.stop)
+	})
+
+	h.mutex.Lock()
+	started := h.started
+	h.mutex.Unlock()
+
+	if started {
+		<-h.done
+	}
+}
@@ -0,0 +1,134 @@
+package sqs_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/sqs"
+)
+
+func TestHeartbeat(t *testing.T) {
+	message := &sqsLib.Message{
+		ReceiptHandle: aws.String("handle_1"),
+	}
+
+	t.Run("ExtendsVisibilityUntilStopped", func(t *testing.T) {
+		var mutex sync.Mutex
+		extensions := 0
+
+		mock := MockSDKClient{
+			mockChangeMessageVisibility: func(input *sqsLib.ChangeMessageVisibilityInput) (*sqsLib.ChangeMessageVisibilityOutput, error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				assert.Equal(t, "handle_1", *input.ReceiptHandle)
+				assert.Equal(t, int64(30), *input.VisibilityTimeout)
+				extensions++
+
+				return &sqsLib.ChangeMessageVisibilityOutput{}, nil
+			},
+		}
+
+		heartbeat, err := sqs.NewHeartbeat(NewTestClient(&mock), "foo", message, 30*time.Second, 10*time.Millisecond, nil)
+		assert.NoError(t, err)
+
+		heartbeat.Start(context.Background())
+
+		time.Sleep(55 * time.Millisecond)
+		heartbeat.Stop()
+
+		mutex.Lock()
+		extensionsAtStop := extensions
+		mutex.Unlock()
+
+		time.Sleep(30 * time.Millisecond)
+
+		assert.True(t, extensionsAtStop >= 2)
+		assert.Equal(t, extensionsAtStop, extensions)
+	})
+
+	t.Run("StopsWhenContextCancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		heartbeat, err := sqs.NewHeartbeat(NewTestClient(nil), "foo", message, 30*time.Second, 10*time.Millisecond, nil)
+		assert.NoError(t, err)
+
+		heartbeat.Start(ctx)
+		cancel()
+
+		stopped := make(chan struct{})
+		go func() {
+			heartbeat.Stop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("Expected heartbeat to stop after context was cancelled")
+		}
+	})
+
+	t.Run("ReportsExtensionErrors", func(t *testing.T) {
+		var mutex sync.Mutex
+		var extensionErrors []error
+
+		mock := MockSDKClient{
+			mockChangeMessageVisibility: func(input *sqsLib.ChangeMessageVisibilityInput) (*sqsLib.ChangeMessageVisibilityOutput, error) {
+				return nil, errors.New("ReceiptHandleIsInvalid")
+			},
+		}
+
+		heartbeat, err := sqs.NewHeartbeat(NewTestClient(&mock), "foo", message, 30*time.Second, 10*time.Millisecond, func(err error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			extensionErrors = append(extensionErrors, err)
+		})
+		assert.NoError(t, err)
+
+		heartbeat.Start(context.Background())
+
+		time.Sleep(25 * time.Millisecond)
+		heartbeat.Stop()
+
+		assert.NotEmpty(t, extensionErrors)
+	})
+
+	t.Run("RejectsExtensionShorterThanASecond", func(t *testing.T) {
+		_, err := sqs.NewHeartbeat(NewTestClient(nil), "foo", message, 0, 0, nil)
+
+		assert.EqualError(t, err, "Unable to create heartbeat, extension of 0s is shorter than a second")
+	})
+
+	t.Run("RejectsInvalidInterval", func(t *testing.T) {
+		for _, interval := range []time.Duration{-time.Second, 30 * time.Second} {
+			_, err := sqs.NewHeartbeat(NewTestClient(nil), "foo", message, 30*time.Second, interval, nil)
+
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("StopsWithoutStart", func(t *testing.T) {
+		heartbeat, err := sqs.NewHeartbeat(NewTestClient(nil), "foo", message, 30*time.Second, 0, nil)
+		assert.NoError(t, err)
+
+		stopped := make(chan struct{})
+		go func() {
+			heartbeat.Stop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("Expected Stop to return without Start")
+		}
+	})
+}
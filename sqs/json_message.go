@@ -0,0 +1,136 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+	"github.com/vidsy/awswrappers"
+)
+
+const (
+	// MessageTypeAttribute is the message attribute used to store the type of
+	// a JSON message body.
+	MessageTypeAttribute = "MessageType"
+)
+
+type (
+	// JSONMessage wraps a message received from SQS that has a JSON body
+	// sent by SendJSON.
+	JSONMessage struct {
+		*sqsLib.Message
+	}
+)
+
+var (
+	// ErrMessageTypeMismatch is the cause of the error returned from
+	// JSONMessage.Decode when the message type isn't the expected type.
+	ErrMessageTypeMismatch = errors.New("Message type does not match expected type")
+)
+
+// SendJSON marshals the given value to JSON and sends it on the given queue
+// with the message type set in the MessageType attribute.
+func (s Client) SendJSON(queueName string, messageType string, v interface{}) (string, error) {
+	return s.SendJSONWithContext(context.Background(), queueName, messageType, v)
+}
+
+// SendJSONWithContext is SendJSON with a context that can cancel the
+// request.
+func (s Client) SendJSONWithContext(ctx context.Context, queueName string, messageType string, v interface{}) (string, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "sqs.SendJSON")
+	span.SetAttribute("queue", queueName)
+	span.SetAttribute("message_type", messageType)
+
+	messageID, err := s.sendJSON(ctx, queueName, messageType, v)
+	if err == nil {
+		span.SetAttribute("message_id", messageID)
+	}
+
+	awswrappers.EndSpan(span, err)
+
+	return messageID, err
+}
+
+func (s Client) sendJSON(ctx context.Context, queueName string, messageType string, v interface{}) (string, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Wrapf(
+			err,
+			"Problem marshaling message of type '%s' to JSON",
+			messageType,
+		)
+	}
+
+	queueURL, err := s.queueURL(ctx, queueName)
+	if err != nil {
+		return "", err
+	}
+
+	messageBody, messageAttributes, err := s.offloadPayload(ctx, body, s.injectTraceContext(ctx, map[string]*sqsLib.MessageAttributeValue{
+		MessageTypeAttribute: {
+			DataType:    aws.String("String"),
+			StringValue: aws.String(messageType),
+		},
+	}))
+	if err != nil {
+		return "", err
+	}
+
+	params := &sqsLib.SendMessageInput{
+		MessageAttributes: messageAttributes,
+		MessageBody:       aws.String(messageBody),
+		QueueUrl:          aws.String(queueURL),
+	}
+
+	resp, err := s.SQSAPI.SendMessageWithContext(ctx, params)
+	if err != nil {
+		s.discardPayload(ctx, messageBody, messageAttributes)
+		return "", err
+	}
+
+	return *resp.MessageId, nil
+}
+
+// NewJSONMessage wraps the given message.
+func NewJSONMessage(message *sqsLib.Message) JSONMessage {
+	return JSONMessage{
+		message,
+	}
+}
+
+// Type returns the message type from the MessageType attribute, or an empty
+// string if it wasn't set.
+func (m JSONMessage) Type() string {
+	attribute, ok := m.MessageAttributes[MessageTypeAttribute]
+	if !ok || attribute == nil {
+		return ""
+	}
+
+	return aws.StringValue(attribute.StringValue)
+}
+
+// Decode unmarshals the message body into v, returning an error caused by
+// ErrMessageTypeMismatch if the message isn't of the expected type.
+func (m JSONMessage) Decode(messageType string, v interface{}) error {
+	if m.Type() != messageType {
+		return errors.Wrapf(
+			ErrMessageTypeMismatch,
+			"Expected message type '%s', got '%s'",
+			messageType,
+			m.Type(),
+		)
+	}
+
+	err := json.Unmarshal([]byte(aws.StringValue(m.Body)), v)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"Problem unmarshaling message of type '%s' from JSON",
+			messageType,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package sqs_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/sqs"
+)
+
+type (
+	TestJSONPayload struct {
+		ID string `json:"id"`
+	}
+)
+
+func TestJSONMessage(t *testing.T) {
+	t.Run(".SendJSON()", func(t *testing.T) {
+		t.Run("SendsBodyAndTypeAttribute", func(t *testing.T) {
+			var sentInput *sqsLib.SendMessageInput
+
+			mock := MockSDKClient{
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					sentInput = input
+					return &sqsLib.SendMessageOutput{
+						MessageId: aws.String("1"),
+					}, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			messageID, err := client.SendJSON("foo", "video.created", TestJSONPayload{ID: "123"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "1", messageID)
+			assert.Equal(t, `{"id":"123"}`, *sentInput.MessageBody)
+			assert.Equal(t, "video.created", *sentInput.MessageAttributes[sqs.MessageTypeAttribute].StringValue)
+		})
+
+		t.Run("ReturnsErrorWhenValueCannotBeMarshaled", func(t *testing.T) {
+			client := NewTestClient(nil)
+			_, err := client.SendJSON("foo", "video.created", make(chan int))
+
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run(".Decode()", func(t *testing.T) {
+		message := sqs.NewJSONMessage(&sqsLib.Message{
+			Body: aws.String(`{"id":"123"}`),
+			MessageAttributes: map[string]*sqsLib.MessageAttributeValue{
+				sqs.MessageTypeAttribute: {
+					DataType:    aws.String("String"),
+					StringValue: aws.String("video.created"),
+				},
+			},
+		})
+
+		t.Run("UnmarshalsBody", func(t *testing.T) {
+			var payload TestJSONPayload
+			err := message.Decode("video.created", &payload)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "123", payload.ID)
+		})
+
+		t.Run("RejectsUnexpectedType", func(t *testing.T) {
+			var payload TestJSONPayload
+			err := message.Decode("video.deleted", &payload)
+
+			assert.Equal(t, sqs.ErrMessageTypeMismatch, errors.Cause(err))
+			assert.Empty(t, payload.ID)
+		})
+
+		t.Run("RejectsMessageWithoutType", func(t *testing.T) {
+			untypedMessage := sqs.NewJSONMessage(&sqsLib.Message{
+				Body: aws.String(`{"id":"123"}`),
+			})
+
+			var payload TestJSONPayload
+			err := untypedMessage.Decode("video.created", &payload)
+
+			assert.Equal(t, sqs.ErrMessageTypeMismatch, errors.Cause(err))
+		})
+	})
+}
@@ -0,0 +1,241 @@
+package sqs
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+	"github.com/vidsy/awswrappers"
+	"github.com/vidsy/awswrappers/s3"
+)
+
+const (
+	// LargePayloadSizeAttribute is the message attribute set on messages
+	// whose body has been stored in S3, it contains the size of the original
+	// body.
+	LargePayloadSizeAttribute = "SQSLargePayloadSize"
+
+	// MaxMessageSize is the largest message, body and attributes, SQS
+	// accepts.
+	MaxMessageSize = 262144
+
+	largePayloadContentType = "application/octet-stream"
+	receiptHandleBucketMark = "-..s3BucketName..-"
+	receiptHandleKeyMark    = "-..s3Key..-"
+)
+
+type (
+	// LargePayloadStore stores message bodies larger than Threshold in an S3
+	// bucket, sending a pointer to the object in their place.
+	LargePayloadStore struct {
+		Bucket    string
+		Threshold int
+		client    s3iface.S3API
+	}
+
+	payloadPointer struct {
+		Bucket string `json:"s3BucketName"`
+		Key    string `json:"s3Key"`
+	}
+)
+
+// NewLargePayloadStore creates a new LargePayloadStore for the given bucket
+// with a Threshold of MaxMessageSize.
+func NewLargePayloadStore(bucket string, client s3iface.S3API) *LargePayloadStore {
+	return &LargePayloadStore{
+		Bucket:    bucket,
+		Threshold: MaxMessageSize,
+		client:    client,
+	}
+}
+
+func (s Client) offloadPayload(ctx context.Context, body []byte, messageAttributes map[string]*sqsLib.MessageAttributeValue) (string, map[string]*sqsLib.MessageAttributeValue, error) {
+	if s.payloadStore == nil || messageSize(body, messageAttributes) <= s.payloadStore.Threshold {
+		return string(body[:]), messageAttributes, nil
+	}
+
+	key, err := newPayloadKey()
+	if err != nil {
+		return "", nil, err
+	}
+
+	object := s3.NewObject(s.payloadStore.Bucket, key, s.payloadStore.client).WithTracer(s.tracer)
+
+	err = object.PutWithContext(ctx, bytes.NewReader(body), largePayloadContentType)
+	if err != nil {
+		return "", nil, errors.Wrapf(
+			err,
+			"Problem storing large payload in bucket '%s'",
+			s.payloadStore.Bucket,
+		)
+	}
+
+	pointer, err := json.Marshal(payloadPointer{
+		Bucket: object.Bucket,
+		Key:    object.Key,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	offloadedAttributes := make(map[string]*sqsLib.MessageAttributeValue, len(messageAttributes)+1)
+	for name, value := range messageAttributes {
+		offloadedAttributes[name] = value
+	}
+
+	offloadedAttributes[LargePayloadSizeAttribute] = &sqsLib.MessageAttributeValue{
+		DataType:    aws.String("Number"),
+		StringValue: aws.String(strconv.Itoa(len(body))),
+	}
+
+	return string(pointer[:]), offloadedAttributes, nil
+}
+
+// discardPayload deletes the S3 object of a message body stored by
+// offloadPayload once the message couldn't be sent. The send error is what
+// the caller returns, so a failed delete is only logged.
+func (s Client) discardPayload(ctx context.Context, messageBody string, messageAttributes map[string]*sqsLib.MessageAttributeValue) {
+	if _, ok := messageAttributes[LargePayloadSizeAttribute]; !ok || s.payloadStore == nil {
+		return
+	}
+
+	var pointer payloadPointer
+
+	err := json.Unmarshal([]byte(messageBody), &pointer)
+	if err == nil {
+		err = s.deletePayload(detachedContext{ctx}, &pointer)
+	}
+
+	if err != nil {
+		s.logger.Log("Unable to delete large payload of unsent message", awswrappers.Fields{
+			"bucket": s.payloadStore.Bucket,
+			"error":  err.Error(),
+		})
+	}
+}
+
+func (s Client) resolvePayload(ctx context.Context, message *sqsLib.Message) error {
+	if _, ok := message.MessageAttributes[LargePayloadSizeAttribute]; !ok {
+		return nil
+	}
+
+	if s.payloadStore == nil {
+		return errors.Errorf(
+			"Unable to resolve large payload of message '%s', no LargePayloadStore is set",
+			aws.StringValue(message.MessageId),
+		)
+	}
+
+	var pointer payloadPointer
+
+	err := json.Unmarshal([]byte(aws.StringValue(message.Body)), &pointer)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"Problem unmarshaling large payload pointer for message '%s'",
+			aws.StringValue(message.MessageId),
+		)
+	}
+
+	body, err := s3.NewObject(pointer.Bucket, pointer.Key, s.payloadStore.client).WithTracer(s.tracer).GetWithContext(ctx)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"Problem fetching large payload '%s/%s'",
+			pointer.Bucket,
+			pointer.Key,
+		)
+	}
+	defer body.Close()
+
+	bodyBytes, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	delete(message.MessageAttributes, LargePayloadSizeAttribute)
+	message.Body = aws.String(string(bodyBytes[:]))
+	message.ReceiptHandle = aws.String(
+		encodeReceiptHandle(pointer, aws.StringValue(message.ReceiptHandle)),
+	)
+
+	return nil
+}
+
+func (s Client) deletePayload(ctx context.Context, pointer *payloadPointer) error {
+	if pointer == nil || s.payloadStore == nil {
+		return nil
+	}
+
+	err := s3.NewObject(pointer.Bucket, pointer.Key, s.payloadStore.client).WithTracer(s.tracer).DeleteWithContext(ctx)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"Problem deleting large payload '%s/%s'",
+			pointer.Bucket,
+			pointer.Key,
+		)
+	}
+
+	return nil
+}
+
+func encodeReceiptHandle(pointer payloadPointer, receiptHandle string) string {
+	return receiptHandleBucketMark + pointer.Bucket + receiptHandleBucketMark +
+		receiptHandleKeyMark + pointer.Key + receiptHandleKeyMark +
+		receiptHandle
+}
+
+func decodeReceiptHandle(receiptHandle *string) (*payloadPointer, *string) {
+	handle := aws.StringValue(receiptHandle)
+	if !strings.HasPrefix(handle, receiptHandleBucketMark) {
+		return nil, receiptHandle
+	}
+
+	bucketParts := strings.SplitN(handle, receiptHandleBucketMark, 3)
+	if len(bucketParts) != 3 || !strings.HasPrefix(bucketParts[2], receiptHandleKeyMark) {
+		return nil, receiptHandle
+	}
+
+	keyParts := strings.SplitN(bucketParts[2], receiptHandleKeyMark, 3)
+	if len(keyParts) != 3 {
+		return nil, receiptHandle
+	}
+
+	pointer := &payloadPointer{
+		Bucket: bucketParts[1],
+		Key:    keyParts[1],
+	}
+
+	return pointer, aws.String(keyParts[2])
+}
+
+func messageSize(body []byte, messageAttributes map[string]*sqsLib.MessageAttributeValue) int {
+	size := len(body)
+	for name, value := range messageAttributes {
+		size += len(name) + len(aws.StringValue(value.DataType)) +
+			len(aws.StringValue(value.StringValue)) + len(value.BinaryValue)
+	}
+
+	return size
+}
+
+func newPayloadKey() (string, error) {
+	key := make([]byte, 16)
+
+	_, err := rand.Read(key)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(key), nil
+}
@@ -0,0 +1,332 @@
+package sqs_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	s3Lib "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers"
+	"github.com/vidsy/awswrappers/sqs"
+)
+
+type (
+	MockS3Client struct {
+		s3iface.S3API
+		failedDeletes map[string]bool
+		objects       map[string][]byte
+		putLimit      int
+	}
+)
+
+func NewMockS3Client() *MockS3Client {
+	return &MockS3Client{
+		objects: make(map[string][]byte),
+	}
+}
+
+func (m *MockS3Client) PutObjectWithContext(ctx aws.Context, input *s3Lib.PutObjectInput, opts ...request.Option) (*s3Lib.PutObjectOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.putLimit > 0 && len(m.objects) >= m.putLimit {
+		return nil, errors.New("Put error")
+	}
+
+	body, _ := ioutil.ReadAll(input.Body)
+	m.objects[*input.Bucket+"/"+*input.Key] = body
+
+	return &s3Lib.PutObjectOutput{}, nil
+}
+
+func (m *MockS3Client) GetObjectWithContext(ctx aws.Context, input *s3Lib.GetObjectInput, opts ...request.Option) (*s3Lib.GetObjectOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	body := m.objects[*input.Bucket+"/"+*input.Key]
+
+	return &s3Lib.GetObjectOutput{
+		Body: ioutil.NopCloser(bytes.NewReader(body)),
+	}, nil
+}
+
+func (m *MockS3Client) DeleteObjectWithContext(ctx aws.Context, input *s3Lib.DeleteObjectInput, opts ...request.Option) (*s3Lib.DeleteObjectOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if m.failedDeletes[*input.Key] {
+		return nil, errors.New("Delete error")
+	}
+
+	delete(m.objects, *input.Bucket+"/"+*input.Key)
+
+	return &s3Lib.DeleteObjectOutput{}, nil
+}
+
+func NewLargePayloadTestClient(mockClient *MockSDKClient, store *sqs.LargePayloadStore) *sqs.Client {
+	return sqs.NewClientWithOptions(
+		sqs.WithAPI(mockClient),
+		sqs.WithClientConfig(&sqs.ClientConfig{
+			QueueEndpoint: "http://www.test.com",
+		}),
+		sqs.WithDevelopmentMode(true),
+		sqs.WithLargePayloadStore(store),
+	)
+}
+
+func TestLargePayloadStore(t *testing.T) {
+	largeBody := []byte(strings.Repeat("a", sqs.MaxMessageSize+1))
+
+	t.Run("StoresOversizedBodiesInS3", func(t *testing.T) {
+		var sentInput *sqsLib.SendMessageInput
+		mockS3Client := NewMockS3Client()
+
+		mock := MockSDKClient{
+			mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+				sentInput = input
+				return &sqsLib.SendMessageOutput{MessageId: aws.String("1")}, nil
+			},
+		}
+
+		client := NewLargePayloadTestClient(&mock, sqs.NewLargePayloadStore("payloads", mockS3Client))
+
+		_, err := client.SendNewMessage("foo", largeBody)
+
+		assert.NoError(t, err)
+		assert.Len(t, mockS3Client.objects, 1)
+		assert.Contains(t, *sentInput.MessageBody, `"s3BucketName":"payloads"`)
+		assert.Equal(t, "262145", *sentInput.MessageAttributes[sqs.LargePayloadSizeAttribute].StringValue)
+	})
+
+	t.Run("SendsSmallBodiesDirectly", func(t *testing.T) {
+		var sentInput *sqsLib.SendMessageInput
+		mockS3Client := NewMockS3Client()
+
+		mock := MockSDKClient{
+			mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+				sentInput = input
+				return &sqsLib.SendMessageOutput{MessageId: aws.String("1")}, nil
+			},
+		}
+
+		client := NewLargePayloadTestClient(&mock, sqs.NewLargePayloadStore("payloads", mockS3Client))
+
+		_, err := client.SendNewMessage("foo", []byte("small"))
+
+		assert.NoError(t, err)
+		assert.Empty(t, mockS3Client.objects)
+		assert.Equal(t, "small", *sentInput.MessageBody)
+	})
+
+	t.Run("ResolvesBodyOnReceiveAndDeletesObject", func(t *testing.T) {
+		var sentInput *sqsLib.SendMessageInput
+		var deletedHandle string
+		mockS3Client := NewMockS3Client()
+
+		mock := MockSDKClient{
+			mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+				sentInput = input
+				return &sqsLib.SendMessageOutput{MessageId: aws.String("1")}, nil
+			},
+			mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+				return &sqsLib.ReceiveMessageOutput{
+					Messages: []*sqsLib.Message{
+						{
+							Body:              sentInput.MessageBody,
+							MessageAttributes: sentInput.MessageAttributes,
+							MessageId:         aws.String("1"),
+							ReceiptHandle:     aws.String("handle_1"),
+						},
+					},
+				}, nil
+			},
+			mockDeleteMessge: func(input *sqsLib.DeleteMessageInput) (*sqsLib.DeleteMessageOutput, error) {
+				deletedHandle = *input.ReceiptHandle
+				return &sqsLib.DeleteMessageOutput{}, nil
+			},
+		}
+
+		client := NewLargePayloadTestClient(&mock, sqs.NewLargePayloadStore("payloads", mockS3Client))
+
+		_, err := client.SendNewMessage("foo", largeBody)
+		assert.NoError(t, err)
+
+		message, err := client.ReceiveMessage("foo")
+		assert.NoError(t, err)
+		assert.Equal(t, string(largeBody), *message.Body)
+
+		err = client.DeleteMessage("foo", message.ReceiptHandle)
+		assert.NoError(t, err)
+		assert.Equal(t, "handle_1", deletedHandle)
+		assert.Empty(t, mockS3Client.objects)
+	})
+
+	t.Run("DeletesObjectWhenSendFails", func(t *testing.T) {
+		mockS3Client := NewMockS3Client()
+
+		mock := MockSDKClient{
+			mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+				return nil, errors.New("Send error")
+			},
+		}
+
+		client := NewLargePayloadTestClient(&mock, sqs.NewLargePayloadStore("payloads", mockS3Client))
+
+		_, err := client.SendNewMessage("foo", largeBody)
+
+		assert.EqualError(t, err, "Send error")
+		assert.Empty(t, mockS3Client.objects)
+	})
+
+	t.Run("DeletesObjectsOfFailedBatchEntries", func(t *testing.T) {
+		mockS3Client := NewMockS3Client()
+
+		mock := MockSDKClient{
+			mockSendMessageBatch: func(input *sqsLib.SendMessageBatchInput) (*sqsLib.SendMessageBatchOutput, error) {
+				output := successfulSendMessageBatchOutput(input.Entries[:1])
+				output.Failed = []*sqsLib.BatchResultErrorEntry{
+					{
+						Code:        aws.String("InvalidMessageContents"),
+						Id:          input.Entries[1].Id,
+						SenderFault: aws.Bool(true),
+					},
+				}
+
+				return output, nil
+			},
+		}
+
+		client := NewLargePayloadTestClient(&mock, sqs.NewLargePayloadStore("payloads", mockS3Client))
+
+		report, err := client.SendMessageBatch("foo", [][]byte{largeBody, largeBody})
+
+		assert.NoError(t, err)
+		assert.Len(t, report.Successful, 1)
+		assert.Len(t, report.Failed, 1)
+		assert.Len(t, mockS3Client.objects, 1)
+	})
+
+	t.Run("DeletesStoredObjectsWhenBatchOffloadFails", func(t *testing.T) {
+		mockS3Client := NewMockS3Client()
+		mockS3Client.putLimit = 2
+
+		mock := MockSDKClient{
+			mockSendMessageBatch: func(input *sqsLib.SendMessageBatchInput) (*sqsLib.SendMessageBatchOutput, error) {
+				t.Fatal("Expected no batch to be sent")
+				return nil, nil
+			},
+		}
+
+		client := NewLargePayloadTestClient(&mock, sqs.NewLargePayloadStore("payloads", mockS3Client))
+
+		_, err := client.SendMessageBatch("foo", [][]byte{largeBody, largeBody, largeBody})
+
+		assert.EqualError(t, err, "Problem storing large payload in bucket 'payloads': Put error")
+		assert.Empty(t, mockS3Client.objects)
+	})
+
+	t.Run("DeletesEveryPayloadOfDeletedBatch", func(t *testing.T) {
+		mockS3Client := NewMockS3Client()
+		mockS3Client.failedDeletes = map[string]bool{"key_0": true}
+
+		var receiptHandles []*string
+		for i := 0; i < 3; i++ {
+			key := fmt.Sprintf("key_%d", i)
+			mockS3Client.objects["payloads/"+key] = largeBody
+			receiptHandles = append(receiptHandles, aws.String(
+				"-..s3BucketName..-payloads-..s3BucketName..--..s3Key..-"+key+"-..s3Key..-handle",
+			))
+		}
+
+		mock := MockSDKClient{
+			mockDeleteMessageBatch: func(input *sqsLib.DeleteMessageBatchInput) (*sqsLib.DeleteMessageBatchOutput, error) {
+				output := &sqsLib.DeleteMessageBatchOutput{}
+				for _, entry := range input.Entries {
+					output.Successful = append(output.Successful, &sqsLib.DeleteMessageBatchResultEntry{Id: entry.Id})
+				}
+
+				return output, nil
+			},
+		}
+
+		client := NewLargePayloadTestClient(&mock, sqs.NewLargePayloadStore("payloads", mockS3Client))
+
+		report, err := client.DeleteMessageBatch("foo", receiptHandles)
+
+		assert.EqualError(t, err, "Problem deleting 1 of 3 large payloads, the first error was: Problem deleting large payload 'payloads/key_0': Delete error")
+		assert.Len(t, report.Successful, 3)
+		assert.Equal(t, []string{"payloads/key_0"}, objectKeys(mockS3Client.objects))
+	})
+
+	t.Run("SkipsOffloadedMessageWithoutStore", func(t *testing.T) {
+		var loggedFields awswrappers.Fields
+
+		mock := MockSDKClient{
+			mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+				return &sqsLib.ReceiveMessageOutput{
+					Messages: []*sqsLib.Message{
+						{
+							Body: aws.String(`{"s3BucketName":"payloads","s3Key":"bar"}`),
+							MessageAttributes: map[string]*sqsLib.MessageAttributeValue{
+								sqs.LargePayloadSizeAttribute: {
+									DataType:    aws.String("Number"),
+									StringValue: aws.String("262145"),
+								},
+							},
+							MessageId:     aws.String("1"),
+							ReceiptHandle: aws.String("handle_1"),
+						},
+						{
+							Body:          aws.String("small"),
+							MessageId:     aws.String("2"),
+							ReceiptHandle: aws.String("handle_2"),
+						},
+					},
+				}, nil
+			},
+		}
+
+		client := sqs.NewClientWithOptions(
+			sqs.WithAPI(&mock),
+			sqs.WithClientConfig(&sqs.ClientConfig{
+				MaxNumberOfMessages: 10,
+				QueueEndpoint:       "http://www.test.com",
+			}),
+			sqs.WithDevelopmentMode(true),
+			sqs.WithLogger(awswrappers.LoggerFunc(func(message string, fields awswrappers.Fields) {
+				if message == "Skipping SQS message that couldn't be received" {
+					loggedFields = fields
+				}
+			})),
+		)
+
+		messages, err := client.ReceiveMessages("foo")
+
+		assert.NoError(t, err)
+		if assert.Len(t, messages, 1) {
+			assert.Equal(t, "2", *messages[0].MessageId)
+		}
+		assert.Equal(t, "1", loggedFields["message_id"])
+		assert.Equal(t, "Unable to resolve large payload of message '1', no LargePayloadStore is set", loggedFields["error"])
+	})
+}
+
+func objectKeys(objects map[string][]byte) []string {
+	keys := make([]string, 0, len(objects))
+	for key := range objects {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
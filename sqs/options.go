@@ -0,0 +1,171 @@
+package sqs
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/vidsy/awswrappers"
+)
+
+type (
+	// Option configures a Client created by NewClientWithOptions.
+	Option func(*options)
+
+	options struct {
+		api               sqsiface.SQSAPI
+		config            *ClientConfig
+		developmentMode   bool
+		endpoint          string
+		healthCheckQueues []string
+		largePayloadStore *LargePayloadStore
+		logger            awswrappers.Logger
+		metrics           awswrappers.Metrics
+		session           *session.Session
+		tracer            awswrappers.Tracer
+	}
+)
+
+// WithAPI uses the given SQS API instead of creating one, the session and
+// endpoint options are ignored.
+func WithAPI(api sqsiface.SQSAPI) Option {
+	return func(o *options) {
+		o.api = api
+	}
+}
+
+// WithClientConfig sets the config of the Client.
+func WithClientConfig(config *ClientConfig) Option {
+	return func(o *options) {
+		o.config = config
+	}
+}
+
+// WithDevelopmentMode sends requests to ClientConfig.QueueEndpoint and falls
+// back to building queue URLs from it when they can't be resolved.
+func WithDevelopmentMode(developmentMode bool) Option {
+	return func(o *options) {
+		o.developmentMode = developmentMode
+	}
+}
+
+// WithEndpoint sends requests to the given endpoint, taking precedence over
+// ClientConfig.QueueEndpoint in development mode.
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithHealthCheckQueues sets the queues whose attributes are fetched by
+// Client.HealthCheck.
+func WithHealthCheckQueues(names ...string) Option {
+	return func(o *options) {
+		o.healthCheckQueues = names
+	}
+}
+
+// WithLargePayloadStore enables storing oversized message bodies in S3,
+// bodies are resolved on receive and the S3 object is deleted with the
+// message.
+func WithLargePayloadStore(store *LargePayloadStore) Option {
+	return func(o *options) {
+		o.largePayloadStore = store
+	}
+}
+
+// WithLogger logs the requests made by the Client and its retries to the
+// given logger, it has no effect when the API is given with WithAPI.
+func WithLogger(logger awswrappers.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithMetrics records the requests made by the Client to the given
+// metrics, it has no effect when the API is given with WithAPI.
+func WithMetrics(metrics awswrappers.Metrics) Option {
+	return func(o *options) {
+		o.metrics = metrics
+	}
+}
+
+// WithSession creates the SQS API from the given session, see
+// awswrappers.NewSession.
+func WithSession(sess *session.Session) Option {
+	return func(o *options) {
+		o.session = sess
+	}
+}
+
+// WithTracer starts a span for each call made with the Client, see
+// awswrappers.Tracer.
+func WithTracer(tracer awswrappers.Tracer) Option {
+	return func(o *options) {
+		o.tracer = tracer
+	}
+}
+
+// NewClientWithOptions creates a new wrapper from the given options, by
+// default an empty ClientConfig and a new default session are used. With an
+// empty ClientConfig a single message is received at a time and the
+// visibility timeout of the queue applies.
+func NewClientWithOptions(opts ...Option) *Client {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.config == nil {
+		o.config = &ClientConfig{}
+	}
+
+	logger := o.logger
+	if logger == nil {
+		logger = awswrappers.NopLogger{}
+	}
+
+	tracer := o.tracer
+	if tracer == nil {
+		tracer = awswrappers.NopTracer{}
+	}
+
+	if o.api == nil {
+		if o.session == nil {
+			o.session = session.New()
+		}
+
+		endpoint := o.endpoint
+		if endpoint == "" && o.developmentMode {
+			endpoint = o.config.QueueEndpoint
+		}
+
+		var sqsClient *sqsLib.SQS
+
+		if endpoint != "" {
+			sqsClient = sqsLib.New(o.session, awswrappers.DevelopmentConfig(endpoint))
+		} else {
+			sqsClient = sqsLib.New(o.session)
+		}
+
+		if o.logger != nil {
+			awswrappers.LogRequests(&sqsClient.Handlers, o.logger)
+		}
+
+		if o.metrics != nil {
+			awswrappers.RecordRequests(&sqsClient.Handlers, o.metrics)
+		}
+
+		o.api = sqsClient
+	}
+
+	return &Client{
+		o.api,
+		o.config,
+		o.developmentMode,
+		newQueueURLCache(),
+		o.largePayloadStore,
+		logger,
+		tracer,
+		o.healthCheckQueues,
+	}
+}
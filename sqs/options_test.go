@@ -0,0 +1,95 @@
+package sqs_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers"
+	"github.com/vidsy/awswrappers/sqs"
+)
+
+func TestOptions(t *testing.T) {
+	t.Run(".NewClientWithOptions()", func(t *testing.T) {
+		t.Run("UsesGivenAPI", func(t *testing.T) {
+			mock := &MockSDKClient{}
+			client := sqs.NewClientWithOptions(sqs.WithAPI(mock))
+
+			assert.Equal(t, mock, client.SQSAPI)
+		})
+
+		t.Run("ReceivesWithDefaultConfig", func(t *testing.T) {
+			var receiveInput *sqsLib.ReceiveMessageInput
+
+			mock := &MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					return &sqsLib.GetQueueUrlOutput{QueueUrl: aws.String("http://sqs/foo")}, nil
+				},
+				mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+					receiveInput = input
+					return &sqsLib.ReceiveMessageOutput{}, nil
+				},
+			}
+
+			client := sqs.NewClientWithOptions(sqs.WithAPI(mock))
+			_, err := client.ReceiveMessages("foo")
+
+			assert.NoError(t, err)
+			if assert.NotNil(t, receiveInput) {
+				assert.Equal(t, int64(1), *receiveInput.MaxNumberOfMessages)
+				assert.Nil(t, receiveInput.VisibilityTimeout)
+			}
+		})
+
+		t.Run("UsesConfigEndpointInDevelopmentMode", func(t *testing.T) {
+			client := sqs.NewClientWithOptions(
+				sqs.WithClientConfig(&sqs.ClientConfig{QueueEndpoint: "http://localhost:4576"}),
+				sqs.WithDevelopmentMode(true),
+			)
+
+			assert.Equal(t, "http://localhost:4576", client.SQSAPI.(*sqsLib.SQS).Endpoint)
+		})
+
+		t.Run("EndpointTakesPrecedence", func(t *testing.T) {
+			client := sqs.NewClientWithOptions(
+				sqs.WithClientConfig(&sqs.ClientConfig{QueueEndpoint: "http://localhost:4576"}),
+				sqs.WithDevelopmentMode(true),
+				sqs.WithEndpoint("http://localhost:9324"),
+			)
+
+			assert.Equal(t, "http://localhost:9324", client.SQSAPI.(*sqsLib.SQS).Endpoint)
+		})
+
+		t.Run("RecordsMetrics", func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`<GetQueueUrlResponse><GetQueueUrlResult><QueueUrl>http://sqs/foo</QueueUrl></GetQueueUrlResult></GetQueueUrlResponse>`))
+			}))
+			defer server.Close()
+
+			sess, err := awswrappers.NewSession(&awswrappers.SessionConfig{
+				AccessKeyID:     "access-key-id",
+				Region:          "eu-west-1",
+				SecretAccessKey: "secret-access-key",
+			})
+			assert.NoError(t, err)
+
+			metrics := awswrappers.NewInMemoryMetrics()
+			client := sqs.NewClientWithOptions(
+				sqs.WithEndpoint(server.URL),
+				sqs.WithMetrics(metrics),
+				sqs.WithSession(sess),
+			)
+
+			queueURL, err := client.QueueURL("foo")
+
+			assert.NoError(t, err)
+			assert.Equal(t, "http://sqs/foo", queueURL)
+			if assert.Len(t, metrics.Requests(), 1) {
+				assert.Equal(t, "GetQueueUrl", metrics.Requests()[0].Operation)
+			}
+		})
+	})
+}
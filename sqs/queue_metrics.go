@@ -0,0 +1,242 @@
+package sqs
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+	"github.com/vidsy/awswrappers"
+)
+
+type (
+	// QueueMetrics holds the approximate depth of a queue, OldestMessageAge
+	// is only set when sampling the oldest message.
+	QueueMetrics struct {
+		QueueName                             string
+		ApproximateNumberOfMessages           int64
+		ApproximateNumberOfMessagesNotVisible int64
+		ApproximateNumberOfMessagesDelayed    int64
+		OldestMessageAge                      time.Duration
+	}
+
+	// QueueMetricsRecorder receives the metrics collected by a
+	// QueueMetricsPoller.
+	QueueMetricsRecorder interface {
+		RecordQueueMetrics(metrics QueueMetrics)
+	}
+
+	// QueueMetricsRecorderFunc adapts a function to a QueueMetricsRecorder.
+	QueueMetricsRecorderFunc func(metrics QueueMetrics)
+
+	// QueueMetricsPoller periodically collects QueueMetrics for a set of
+	// queues and passes them to a QueueMetricsRecorder.
+	QueueMetricsPoller struct {
+		client              *Client
+		errorHandler        func(error)
+		interval            time.Duration
+		queueNames          []string
+		recorder            QueueMetricsRecorder
+		sampleOldestMessage bool
+	}
+)
+
+var (
+	queueMetricsAttributeNames = []*string{
+		aws.String(sqsLib.QueueAttributeNameApproximateNumberOfMessages),
+		aws.String(sqsLib.QueueAttributeNameApproximateNumberOfMessagesNotVisible),
+		aws.String(sqsLib.QueueAttributeNameApproximateNumberOfMessagesDelayed),
+	}
+)
+
+// RecordQueueMetrics calls the function with the metrics.
+func (f QueueMetricsRecorderFunc) RecordQueueMetrics(metrics QueueMetrics) {
+	f(metrics)
+}
+
+// QueueMetrics returns the approximate depth of the given queue.
+//
+// SQS doesn't expose the age of the oldest message outside of CloudWatch, so
+// when sampleOldestMessage is true up to 10 visible messages are received
+// with a visibility timeout of 0 and the oldest SentTimestamp is used. This is
+// only an estimate for standard queues.
+//
+// Sampling is a real receive: it increments the ApproximateReceiveCount of
+// every sampled message, so on a queue with a redrive policy sampling alone
+// moves messages to the dead letter queue once they have been sampled
+// maxReceiveCount times. Only sample queues without a redrive policy, or
+// whose maxReceiveCount is well above the number of samples a message can
+// see before it is consumed.
+func (s Client) QueueMetrics(queueName string, sampleOldestMessage bool) (*QueueMetrics, error) {
+	return s.QueueMetricsWithContext(context.Background(), queueName, sampleOldestMessage)
+}
+
+// QueueMetricsWithContext is QueueMetrics with a context that can cancel the
+// requests.
+func (s Client) QueueMetricsWithContext(ctx context.Context, queueName string, sampleOldestMessage bool) (*QueueMetrics, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "sqs.QueueMetrics")
+	span.SetAttribute("queue", queueName)
+
+	metrics, err := s.queueMetrics(ctx, queueName, sampleOldestMessage)
+	awswrappers.EndSpan(span, err)
+
+	return metrics, err
+}
+
+func (s Client) queueMetrics(ctx context.Context, queueName string, sampleOldestMessage bool) (*QueueMetrics, error) {
+	queueURL, err := s.queueURL(ctx, queueName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.SQSAPI.GetQueueAttributesWithContext(ctx, &sqsLib.GetQueueAttributesInput{
+		AttributeNames: queueMetricsAttributeNames,
+		QueueUrl:       aws.String(queueURL),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Unable to get attributes of queue '%s'",
+			queueName,
+		)
+	}
+
+	metrics := &QueueMetrics{
+		QueueName: queueName,
+	}
+
+	for name, value := range map[string]*int64{
+		sqsLib.QueueAttributeNameApproximateNumberOfMessages:           &metrics.ApproximateNumberOfMessages,
+		sqsLib.QueueAttributeNameApproximateNumberOfMessagesNotVisible: &metrics.ApproximateNumberOfMessagesNotVisible,
+		sqsLib.QueueAttributeNameApproximateNumberOfMessagesDelayed:    &metrics.ApproximateNumberOfMessagesDelayed,
+	} {
+		*value, err = strconv.ParseInt(aws.StringValue(resp.Attributes[name]), 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"Problem parsing attribute '%s' of queue '%s'",
+				name,
+				queueName,
+			)
+		}
+	}
+
+	if sampleOldestMessage && metrics.ApproximateNumberOfMessages > 0 {
+		metrics.OldestMessageAge, err = s.oldestMessageAge(ctx, queueURL)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"Unable to sample oldest message of queue '%s'",
+				queueName,
+			)
+		}
+	}
+
+	return metrics, nil
+}
+
+func (s Client) oldestMessageAge(ctx context.Context, queueURL string) (time.Duration, error) {
+	resp, err := s.SQSAPI.ReceiveMessageWithContext(ctx, &sqsLib.ReceiveMessageInput{
+		AttributeNames: []*string{
+			aws.String(sqsLib.MessageSystemAttributeNameSentTimestamp),
+		},
+		MaxNumberOfMessages: aws.Int64(batchMaxEntries),
+		QueueUrl:            aws.String(queueURL),
+		VisibilityTimeout:   aws.Int64(0),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var oldestSentTimestamp int64
+
+	for _, message := range resp.Messages {
+		sentTimestamp, err := strconv.ParseInt(
+			aws.StringValue(message.Attributes[sqsLib.MessageSystemAttributeNameSentTimestamp]),
+			10,
+			64,
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		if oldestSentTimestamp == 0 || sentTimestamp < oldestSentTimestamp {
+			oldestSentTimestamp = sentTimestamp
+		}
+	}
+
+	if oldestSentTimestamp == 0 {
+		return 0, nil
+	}
+
+	age := time.Since(time.Unix(0, oldestSentTimestamp*int64(time.Millisecond)))
+	if age < 0 {
+		return 0, nil
+	}
+
+	return age, nil
+}
+
+// NewQueueMetricsPoller creates a new QueueMetricsPoller for the given queues
+// polling once per interval, which must be positive. Sampling the oldest
+// message receives messages on every poll, which can move them to a dead
+// letter queue, see Client.QueueMetrics before setting sampleOldestMessage.
+// The errorHandler is called when collecting metrics for a queue fails (it
+// can be nil).
+func NewQueueMetricsPoller(client *Client, queueNames []string, interval time.Duration, sampleOldestMessage bool, recorder QueueMetricsRecorder, errorHandler func(error)) (*QueueMetricsPoller, error) {
+	if interval <= 0 {
+		return nil, errors.Errorf(
+			"Unable to create queue metrics poller, interval of %s isn't positive",
+			interval,
+		)
+	}
+
+	return &QueueMetricsPoller{
+		client:              client,
+		errorHandler:        errorHandler,
+		interval:            interval,
+		queueNames:          queueNames,
+		recorder:            recorder,
+		sampleOldestMessage: sampleOldestMessage,
+	}, nil
+}
+
+// Start collects metrics for every queue immediately and then once per
+// interval, blocking until the context is cancelled.
+func (p QueueMetricsPoller) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p QueueMetricsPoller) poll(ctx context.Context) {
+	for _, queueName := range p.queueNames {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		metrics, err := p.client.QueueMetricsWithContext(ctx, queueName, p.sampleOldestMessage)
+		if err != nil {
+			if p.errorHandler != nil {
+				p.errorHandler(err)
+			}
+
+			continue
+		}
+
+		p.recorder.RecordQueueMetrics(*metrics)
+	}
+}
@@ -0,0 +1,158 @@
+package sqs_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/sqs"
+)
+
+func queueMetricsAttributes(input *sqsLib.GetQueueAttributesInput) (*sqsLib.GetQueueAttributesOutput, error) {
+	return &sqsLib.GetQueueAttributesOutput{
+		Attributes: map[string]*string{
+			"ApproximateNumberOfMessages":           aws.String("12"),
+			"ApproximateNumberOfMessagesNotVisible": aws.String("3"),
+			"ApproximateNumberOfMessagesDelayed":    aws.String("1"),
+		},
+	}, nil
+}
+
+func TestQueueMetrics(t *testing.T) {
+	t.Run(".QueueMetrics()", func(t *testing.T) {
+		t.Run("ReturnsQueueDepth", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockGetQueueAttributes: queueMetricsAttributes,
+				mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+					t.Fatal("Expected oldest message not to be sampled")
+					return nil, nil
+				},
+			}
+
+			metrics, err := NewTestClient(&mock).QueueMetrics("foo", false)
+
+			assert.NoError(t, err)
+			assert.Equal(t, &sqs.QueueMetrics{
+				QueueName:                             "foo",
+				ApproximateNumberOfMessages:           12,
+				ApproximateNumberOfMessagesNotVisible: 3,
+				ApproximateNumberOfMessagesDelayed:    1,
+			}, metrics)
+		})
+
+		t.Run("SamplesOldestMessage", func(t *testing.T) {
+			now := time.Now()
+			var receiveInput *sqsLib.ReceiveMessageInput
+
+			mock := MockSDKClient{
+				mockGetQueueAttributes: queueMetricsAttributes,
+				mockReceiveMessage: func(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+					receiveInput = input
+
+					var messages []*sqsLib.Message
+					for _, age := range []time.Duration{time.Minute, time.Hour, time.Second} {
+						sentTimestamp := now.Add(-age).UnixNano() / int64(time.Millisecond)
+						messages = append(messages, &sqsLib.Message{
+							Attributes: map[string]*string{
+								"SentTimestamp": aws.String(strconv.FormatInt(sentTimestamp, 10)),
+							},
+						})
+					}
+
+					return &sqsLib.ReceiveMessageOutput{Messages: messages}, nil
+				},
+			}
+
+			metrics, err := NewTestClient(&mock).QueueMetrics("foo", true)
+
+			assert.NoError(t, err)
+			assert.Equal(t, int64(0), *receiveInput.VisibilityTimeout)
+			assert.True(t, metrics.OldestMessageAge >= time.Hour)
+			assert.True(t, metrics.OldestMessageAge < time.Hour+time.Minute)
+		})
+
+		t.Run("ReturnsErrorOnClientError", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockGetQueueAttributes: func(input *sqsLib.GetQueueAttributesInput) (*sqsLib.GetQueueAttributesOutput, error) {
+					return nil, errors.New("Client error")
+				},
+			}
+
+			_, err := NewTestClient(&mock).QueueMetrics("foo", false)
+
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("NewQueueMetricsPoller", func(t *testing.T) {
+		t.Run("ReturnsErrorForNonPositiveInterval", func(t *testing.T) {
+			for _, interval := range []time.Duration{0, -time.Second} {
+				_, err := sqs.NewQueueMetricsPoller(NewTestClient(nil), []string{"foo"}, interval, false, nil, nil)
+
+				assert.Error(t, err)
+			}
+		})
+	})
+
+	t.Run(".Start()", func(t *testing.T) {
+		t.Run("RecordsMetricsForEachQueue", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockGetQueueAttributes: queueMetricsAttributes,
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			var mutex sync.Mutex
+			var recorded []sqs.QueueMetrics
+
+			recorder := sqs.QueueMetricsRecorderFunc(func(metrics sqs.QueueMetrics) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				recorded = append(recorded, metrics)
+				if len(recorded) == 4 {
+					cancel()
+				}
+			})
+
+			poller, err := sqs.NewQueueMetricsPoller(NewTestClient(&mock), []string{"foo", "bar"}, 10*time.Millisecond, false, recorder, nil)
+			assert.NoError(t, err)
+
+			poller.Start(ctx)
+
+			assert.Len(t, recorded, 4)
+			assert.Equal(t, "foo", recorded[2].QueueName)
+			assert.Equal(t, "bar", recorded[3].QueueName)
+		})
+
+		t.Run("ReportsErrors", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockGetQueueAttributes: func(input *sqsLib.GetQueueAttributesInput) (*sqsLib.GetQueueAttributesOutput, error) {
+					return nil, errors.New("Client error")
+				},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			var pollErrors []error
+
+			recorder := sqs.QueueMetricsRecorderFunc(func(metrics sqs.QueueMetrics) {
+				t.Fatal("Expected no metrics to be recorded")
+			})
+
+			poller, err := sqs.NewQueueMetricsPoller(NewTestClient(&mock), []string{"foo"}, time.Hour, false, recorder, func(err error) {
+				pollErrors = append(pollErrors, err)
+				cancel()
+			})
+			assert.NoError(t, err)
+
+			poller.Start(ctx)
+
+			assert.Len(t, pollErrors, 1)
+		})
+	})
+}
@@ -0,0 +1,243 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+	"github.com/vidsy/awswrappers"
+)
+
+type (
+	// QueueOptions configures a queue created or updated by EnsureQueue, zero
+	// values leave the SQS default in place. Queues are created as FIFO
+	// queues when their name ends with ".fifo".
+	QueueOptions struct {
+		ContentBasedDeduplication bool
+		DeadLetterQueueName       string
+		MaxReceiveCount           int64
+		MessageRetentionPeriod    int64
+		VisibilityTimeout         int64
+	}
+
+	redrivePolicy struct {
+		DeadLetterTargetArn string      `json:"deadLetterTargetArn"`
+		MaxReceiveCount     interface{} `json:"maxReceiveCount"`
+	}
+)
+
+// EnsureQueue creates the queue with the given options if it doesn't exist,
+// otherwise it updates any attributes of the existing queue that differ from
+// the options. It returns the URL of the queue.
+func (s Client) EnsureQueue(queueName string, options QueueOptions) (string, error) {
+	return s.EnsureQueueWithContext(context.Background(), queueName, options)
+}
+
+// EnsureQueueWithContext is EnsureQueue with a context that can cancel the
+// requests.
+func (s Client) EnsureQueueWithContext(ctx context.Context, queueName string, options QueueOptions) (string, error) {
+	ctx, span := s.tracer.StartSpan(ctx, "sqs.EnsureQueue")
+	span.SetAttribute("queue", queueName)
+
+	queueURL, err := s.ensureQueue(ctx, queueName, options)
+	awswrappers.EndSpan(span, err)
+
+	return queueURL, err
+}
+
+func (s Client) ensureQueue(ctx context.Context, queueName string, options QueueOptions) (string, error) {
+	attributes, err := s.queueAttributes(ctx, queueName, options)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.SQSAPI.GetQueueUrlWithContext(ctx, &sqsLib.GetQueueUrlInput{
+		QueueName: aws.String(queueName),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == sqsLib.ErrCodeQueueDoesNotExist {
+			return s.createQueue(ctx, queueName, attributes)
+		}
+
+		return "", errors.Wrapf(
+			err,
+			"Unable to look up queue '%s'",
+			queueName,
+		)
+	}
+
+	queueURL := aws.StringValue(resp.QueueUrl)
+
+	err = s.updateQueueAttributes(ctx, queueURL, attributes)
+	if err != nil {
+		return "", errors.Wrapf(
+			err,
+			"Unable to update attributes of queue '%s'",
+			queueName,
+		)
+	}
+
+	s.queueURLs.set(queueName, queueURL)
+
+	return queueURL, nil
+}
+
+func (s Client) createQueue(ctx context.Context, queueName string, attributes map[string]string) (string, error) {
+	resp, err := s.SQSAPI.CreateQueueWithContext(ctx, &sqsLib.CreateQueueInput{
+		Attributes: aws.StringMap(attributes),
+		QueueName:  aws.String(queueName),
+	})
+	if err != nil {
+		return "", errors.Wrapf(
+			err,
+			"Unable to create queue '%s'",
+			queueName,
+		)
+	}
+
+	queueURL := aws.StringValue(resp.QueueUrl)
+	s.queueURLs.set(queueName, queueURL)
+
+	return queueURL, nil
+}
+
+func (s Client) updateQueueAttributes(ctx context.Context, queueURL string, attributes map[string]string) error {
+	resp, err := s.SQSAPI.GetQueueAttributesWithContext(ctx, &sqsLib.GetQueueAttributesInput{
+		AttributeNames: []*string{
+			aws.String(sqsLib.QueueAttributeNameAll),
+		},
+		QueueUrl: aws.String(queueURL),
+	})
+	if err != nil {
+		return err
+	}
+
+	currentAttributes := aws.StringValueMap(resp.Attributes)
+	changedAttributes := make(map[string]string)
+
+	for name, value := range attributes {
+		if name == sqsLib.QueueAttributeNameFifoQueue {
+			if currentAttributes[name] != value {
+				return errors.New("An existing queue can't be converted to or from a FIFO queue")
+			}
+
+			continue
+		}
+
+		if !queueAttributeEqual(name, currentAttributes[name], value) {
+			changedAttributes[name] = value
+		}
+	}
+
+	if len(changedAttributes) == 0 {
+		return nil
+	}
+
+	_, err = s.SQSAPI.SetQueueAttributesWithContext(ctx, &sqsLib.SetQueueAttributesInput{
+		Attributes: aws.StringMap(changedAttributes),
+		QueueUrl:   aws.String(queueURL),
+	})
+
+	return err
+}
+
+func (s Client) queueAttributes(ctx context.Context, queueName string, options QueueOptions) (map[string]string, error) {
+	attributes := make(map[string]string)
+
+	if strings.HasSuffix(queueName, fifoQueueSuffix) {
+		attributes[sqsLib.QueueAttributeNameFifoQueue] = "true"
+		attributes[sqsLib.QueueAttributeNameContentBasedDeduplication] = strconv.FormatBool(options.ContentBasedDeduplication)
+	} else if options.ContentBasedDeduplication {
+		return nil, errors.Errorf(
+			"Content based deduplication is only supported by FIFO queues, '%s' is not a FIFO queue",
+			queueName,
+		)
+	}
+
+	if options.VisibilityTimeout > 0 {
+		attributes[sqsLib.QueueAttributeNameVisibilityTimeout] = strconv.FormatInt(options.VisibilityTimeout, 10)
+	}
+
+	if options.MessageRetentionPeriod > 0 {
+		attributes[sqsLib.QueueAttributeNameMessageRetentionPeriod] = strconv.FormatInt(options.MessageRetentionPeriod, 10)
+	}
+
+	if options.DeadLetterQueueName != "" {
+		if options.MaxReceiveCount < 1 {
+			return nil, errors.Errorf(
+				"A MaxReceiveCount is required to use dead letter queue '%s'",
+				options.DeadLetterQueueName,
+			)
+		}
+
+		deadLetterQueueARN, err := s.queueARN(ctx, options.DeadLetterQueueName)
+		if err != nil {
+			return nil, err
+		}
+
+		policy, err := json.Marshal(redrivePolicy{
+			DeadLetterTargetArn: deadLetterQueueARN,
+			MaxReceiveCount:     options.MaxReceiveCount,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		attributes[sqsLib.QueueAttributeNameRedrivePolicy] = string(policy[:])
+	}
+
+	return attributes, nil
+}
+
+func (s Client) queueARN(ctx context.Context, queueName string) (string, error) {
+	queueURL, err := s.queueURL(ctx, queueName)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.SQSAPI.GetQueueAttributesWithContext(ctx, &sqsLib.GetQueueAttributesInput{
+		AttributeNames: []*string{
+			aws.String(sqsLib.QueueAttributeNameQueueArn),
+		},
+		QueueUrl: aws.String(queueURL),
+	})
+	if err != nil {
+		return "", errors.Wrapf(
+			err,
+			"Unable to get ARN of queue '%s'",
+			queueName,
+		)
+	}
+
+	queueARN := aws.StringValue(resp.Attributes[sqsLib.QueueAttributeNameQueueArn])
+	if queueARN == "" {
+		return "", errors.Errorf("No ARN returned for queue '%s'", queueName)
+	}
+
+	return queueARN, nil
+}
+
+func queueAttributeEqual(name string, current string, desired string) bool {
+	if name != sqsLib.QueueAttributeNameRedrivePolicy {
+		return current == desired
+	}
+
+	var currentPolicy, desiredPolicy redrivePolicy
+
+	if json.Unmarshal([]byte(current), &currentPolicy) != nil {
+		return false
+	}
+
+	if json.Unmarshal([]byte(desired), &desiredPolicy) != nil {
+		return false
+	}
+
+	return currentPolicy.DeadLetterTargetArn == desiredPolicy.DeadLetterTargetArn &&
+		fmt.Sprint(currentPolicy.MaxReceiveCount) == fmt.Sprint(desiredPolicy.MaxReceiveCount)
+}
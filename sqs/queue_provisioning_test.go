@@ -0,0 +1,182 @@
+package sqs_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/sqs"
+)
+
+func TestQueueProvisioning(t *testing.T) {
+	t.Run(".EnsureQueue()", func(t *testing.T) {
+		t.Run("CreatesMissingQueue", func(t *testing.T) {
+			var createInput *sqsLib.CreateQueueInput
+
+			mock := MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					return nil, awserr.New(sqsLib.ErrCodeQueueDoesNotExist, "Queue does not exist", nil)
+				},
+				mockCreateQueue: func(input *sqsLib.CreateQueueInput) (*sqsLib.CreateQueueOutput, error) {
+					createInput = input
+					return &sqsLib.CreateQueueOutput{
+						QueueUrl: aws.String("http://www.test.com/foo.fifo"),
+					}, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			queueURL, err := client.EnsureQueue("foo.fifo", sqs.QueueOptions{
+				ContentBasedDeduplication: true,
+				VisibilityTimeout:         60,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "http://www.test.com/foo.fifo", queueURL)
+			assert.Equal(t, map[string]string{
+				"ContentBasedDeduplication": "true",
+				"FifoQueue":                 "true",
+				"VisibilityTimeout":         "60",
+			}, aws.StringValueMap(createInput.Attributes))
+		})
+
+		t.Run("SetsRedrivePolicy", func(t *testing.T) {
+			var createInput *sqsLib.CreateQueueInput
+
+			mock := MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					if *input.QueueName == "foo-dlq" {
+						return &sqsLib.GetQueueUrlOutput{
+							QueueUrl: aws.String("http://www.test.com/foo-dlq"),
+						}, nil
+					}
+
+					return nil, awserr.New(sqsLib.ErrCodeQueueDoesNotExist, "Queue does not exist", nil)
+				},
+				mockGetQueueAttributes: func(input *sqsLib.GetQueueAttributesInput) (*sqsLib.GetQueueAttributesOutput, error) {
+					return &sqsLib.GetQueueAttributesOutput{
+						Attributes: map[string]*string{
+							"QueueArn": aws.String("arn:aws:sqs:eu-west-1:123456789012:foo-dlq"),
+						},
+					}, nil
+				},
+				mockCreateQueue: func(input *sqsLib.CreateQueueInput) (*sqsLib.CreateQueueOutput, error) {
+					createInput = input
+					return &sqsLib.CreateQueueOutput{
+						QueueUrl: aws.String("http://www.test.com/foo"),
+					}, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			_, err := client.EnsureQueue("foo", sqs.QueueOptions{
+				DeadLetterQueueName: "foo-dlq",
+				MaxReceiveCount:     5,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(
+				t,
+				`{"deadLetterTargetArn":"arn:aws:sqs:eu-west-1:123456789012:foo-dlq","maxReceiveCount":5}`,
+				*createInput.Attributes["RedrivePolicy"],
+			)
+		})
+
+		t.Run("UpdatesChangedAttributesOfExistingQueue", func(t *testing.T) {
+			var setInput *sqsLib.SetQueueAttributesInput
+
+			mock := MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					return &sqsLib.GetQueueUrlOutput{
+						QueueUrl: aws.String("http://www.test.com/foo"),
+					}, nil
+				},
+				mockGetQueueAttributes: func(input *sqsLib.GetQueueAttributesInput) (*sqsLib.GetQueueAttributesOutput, error) {
+					return &sqsLib.GetQueueAttributesOutput{
+						Attributes: map[string]*string{
+							"MessageRetentionPeriod": aws.String("345600"),
+							"VisibilityTimeout":      aws.String("30"),
+						},
+					}, nil
+				},
+				mockCreateQueue: func(input *sqsLib.CreateQueueInput) (*sqsLib.CreateQueueOutput, error) {
+					t.Fatal("Expected existing queue not to be created")
+					return nil, nil
+				},
+				mockSetQueueAttributes: func(input *sqsLib.SetQueueAttributesInput) (*sqsLib.SetQueueAttributesOutput, error) {
+					setInput = input
+					return &sqsLib.SetQueueAttributesOutput{}, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			_, err := client.EnsureQueue("foo", sqs.QueueOptions{
+				MessageRetentionPeriod: 345600,
+				VisibilityTimeout:      60,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]string{
+				"VisibilityTimeout": "60",
+			}, aws.StringValueMap(setInput.Attributes))
+		})
+
+		t.Run("LeavesUnchangedQueueAlone", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					return &sqsLib.GetQueueUrlOutput{
+						QueueUrl: aws.String("http://www.test.com/foo"),
+					}, nil
+				},
+				mockGetQueueAttributes: func(input *sqsLib.GetQueueAttributesInput) (*sqsLib.GetQueueAttributesOutput, error) {
+					return &sqsLib.GetQueueAttributesOutput{
+						Attributes: map[string]*string{
+							"QueueArn":          aws.String("arn:aws:sqs:eu-west-1:123456789012:foo"),
+							"RedrivePolicy":     aws.String(`{"deadLetterTargetArn":"arn:aws:sqs:eu-west-1:123456789012:foo","maxReceiveCount":"5"}`),
+							"VisibilityTimeout": aws.String("60"),
+						},
+					}, nil
+				},
+				mockSetQueueAttributes: func(input *sqsLib.SetQueueAttributesInput) (*sqsLib.SetQueueAttributesOutput, error) {
+					t.Fatal("Expected attributes not to be updated")
+					return nil, nil
+				},
+			}
+
+			client := NewTestClient(&mock)
+			_, err := client.EnsureQueue("foo", sqs.QueueOptions{
+				DeadLetterQueueName: "foo",
+				MaxReceiveCount:     5,
+				VisibilityTimeout:   60,
+			})
+
+			assert.NoError(t, err)
+		})
+
+		t.Run("ReturnsErrorForInvalidOptions", func(t *testing.T) {
+			client := NewTestClient(nil)
+
+			_, err := client.EnsureQueue("foo", sqs.QueueOptions{ContentBasedDeduplication: true})
+			assert.Error(t, err)
+
+			_, err = client.EnsureQueue("foo", sqs.QueueOptions{DeadLetterQueueName: "foo-dlq"})
+			assert.Error(t, err)
+		})
+
+		t.Run("ReturnsErrorOnClientError", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					return nil, errors.New("Client error")
+				},
+			}
+
+			client := NewTestClient(&mock)
+			_, err := client.EnsureQueue("foo", sqs.QueueOptions{})
+
+			assert.Error(t, err)
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+	"github.com/vidsy/awswrappers"
+)
+
+type (
+	queueURLCache struct {
+		mutex sync.RWMutex
+		urls  map[string]string
+	}
+)
+
+func newQueueURLCache() *queueURLCache {
+	return &queueURLCache{
+		urls: make(map[string]string),
+	}
+}
+
+func (c *queueURLCache) get(queueName string) (string, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	queueURL, ok := c.urls[queueName]
+	return queueURL, ok
+}
+
+func (c *queueURLCache) set(queueName string, queueURL string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.urls[queueName] = queueURL
+}
+
+// QueueURL returns the URL of the given queue using GetQueueUrl, passing
+// ClientConfig.QueueOwnerAWSAccountID when set for queues in other accounts.
+// URLs are cached per queue name, and in development mode the URL falls back
+// to the queue name appended to ClientConfig.QueueEndpoint when it can't be
+// resolved, which is cached in the same way.
+func (s Client) QueueURL(queueName string) (string, error) {
+	return s.QueueURLWithContext(context.Background(), queueName)
+}
+
+// QueueURLWithContext is QueueURL with a context that can cancel the
+// request.
+func (s Client) QueueURLWithContext(ctx context.Context, queueName string) (string, error) {
+	return s.queueURL(ctx, queueName)
+}
+
+func (s Client) queueURL(ctx context.Context, queueName string) (string, error) {
+	if queueURL, ok := s.queueURLs.get(queueName); ok {
+		return queueURL, nil
+	}
+
+	params := &sqsLib.GetQueueUrlInput{
+		QueueName: aws.String(queueName),
+	}
+
+	if s.clientConfig.QueueOwnerAWSAccountID != "" {
+		params.QueueOwnerAWSAccountId = aws.String(s.clientConfig.QueueOwnerAWSAccountID)
+	}
+
+	resp, err := s.SQSAPI.GetQueueUrlWithContext(ctx, params)
+	if err == nil && (resp == nil || resp.QueueUrl == nil) {
+		err = errors.New("No queue URL returned")
+	}
+
+	if err != nil {
+		if s.developmentMode {
+			queueURL := fmt.Sprintf("%s/%s", s.clientConfig.QueueEndpoint, queueName)
+
+			s.logger.Log("Using development queue URL after failing to resolve it", awswrappers.Fields{
+				"error":     err.Error(),
+				"queue":     queueName,
+				"queue_url": queueURL,
+				"service":   sqsLib.ServiceName,
+			})
+
+			s.queueURLs.set(queueName, queueURL)
+
+			return queueURL, nil
+		}
+
+		return "", errors.Wrapf(
+			err,
+			"Unable to resolve URL for queue '%s'",
+			queueName,
+		)
+	}
+
+	s.queueURLs.set(queueName, *resp.QueueUrl)
+
+	return *resp.QueueUrl, nil
+}
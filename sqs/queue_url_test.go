@@ -0,0 +1,126 @@
+package sqs_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/sqs"
+)
+
+func TestQueueURL(t *testing.T) {
+	t.Run(".QueueURL()", func(t *testing.T) {
+		t.Run("ResolvesWithGetQueueURL", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					assert.Equal(t, "foo", *input.QueueName)
+					assert.Nil(t, input.QueueOwnerAWSAccountId)
+
+					return &sqsLib.GetQueueUrlOutput{
+						QueueUrl: aws.String("https://sqs.eu-west-1.amazonaws.com/123456789012/foo"),
+					}, nil
+				},
+			}
+
+			client := newProductionTestClient(&mock, "")
+			queueURL, err := client.QueueURL("foo")
+
+			assert.NoError(t, err)
+			assert.Equal(t, "https://sqs.eu-west-1.amazonaws.com/123456789012/foo", queueURL)
+		})
+
+		t.Run("PassesQueueOwnerAccountID", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					assert.Equal(t, "210987654321", *input.QueueOwnerAWSAccountId)
+
+					return &sqsLib.GetQueueUrlOutput{
+						QueueUrl: aws.String("https://sqs.eu-west-1.amazonaws.com/210987654321/foo"),
+					}, nil
+				},
+			}
+
+			client := newProductionTestClient(&mock, "210987654321")
+			queueURL, err := client.QueueURL("foo")
+
+			assert.NoError(t, err)
+			assert.Equal(t, "https://sqs.eu-west-1.amazonaws.com/210987654321/foo", queueURL)
+		})
+
+		t.Run("CachesResolvedURLs", func(t *testing.T) {
+			callCount := 0
+			mock := MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					callCount++
+
+					return &sqsLib.GetQueueUrlOutput{
+						QueueUrl: aws.String("https://sqs.eu-west-1.amazonaws.com/123456789012/foo"),
+					}, nil
+				},
+			}
+
+			client := newProductionTestClient(&mock, "")
+			client.QueueURL("foo")
+			client.QueueURL("foo")
+
+			assert.Equal(t, 1, callCount)
+		})
+
+		t.Run("ReturnsErrorWhenUnresolved", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					return nil, errors.New("AWS.SimpleQueueService.NonExistentQueue")
+				},
+			}
+
+			client := newProductionTestClient(&mock, "")
+			_, err := client.QueueURL("foo")
+
+			assert.Error(t, err)
+		})
+
+		t.Run("FallsBackToEndpointInDevelopment", func(t *testing.T) {
+			mock := MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					return nil, errors.New("AWS.SimpleQueueService.NonExistentQueue")
+				},
+			}
+
+			client := NewTestClient(&mock)
+			queueURL, err := client.QueueURL("foo")
+
+			assert.NoError(t, err)
+			assert.Equal(t, "http://www.test.com/foo", queueURL)
+		})
+
+		t.Run("CachesDevelopmentURL", func(t *testing.T) {
+			callCount := 0
+
+			mock := MockSDKClient{
+				mockGetQueueURL: func(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+					callCount++
+					return nil, errors.New("AWS.SimpleQueueService.NonExistentQueue")
+				},
+			}
+
+			client := NewTestClient(&mock)
+			client.QueueURL("foo")
+			queueURL, err := client.QueueURL("foo")
+
+			assert.NoError(t, err)
+			assert.Equal(t, "http://www.test.com/foo", queueURL)
+			assert.Equal(t, 1, callCount)
+		})
+	})
+}
+
+func newProductionTestClient(mockClient *MockSDKClient, queueOwnerAWSAccountID string) *sqs.Client {
+	config := sqs.ClientConfig{
+		QueueEndpoint:          "http://www.test.com",
+		QueueOwnerAWSAccountID: queueOwnerAWSAccountID,
+	}
+
+	return sqs.NewClient(&config, false, mockClient)
+}
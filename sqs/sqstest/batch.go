@@ -0,0 +1,193 @@
+package sqstest
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+)
+
+const (
+	maxBatchEntries = 10
+)
+
+// SendMessageBatch sends up to 10 messages, entries that can't be sent are
+// returned as failed with the error that SendMessage would return.
+func (f *Fake) SendMessageBatch(input *sqsLib.SendMessageBatchInput) (*sqsLib.SendMessageBatchOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	q, err := f.queue(input.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	entryIDs := make([]*string, 0, len(input.Entries))
+	for _, entry := range input.Entries {
+		entryIDs = append(entryIDs, entry.Id)
+	}
+
+	err = validateBatchEntryIDs(entryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &sqsLib.SendMessageBatchOutput{
+		Failed:     []*sqsLib.BatchResultErrorEntry{},
+		Successful: []*sqsLib.SendMessageBatchResultEntry{},
+	}
+
+	for _, entry := range input.Entries {
+		m, err := f.send(
+			q,
+			aws.StringValue(entry.MessageBody),
+			entry.DelaySeconds,
+			entry.MessageAttributes,
+			aws.StringValue(entry.MessageDeduplicationId),
+			aws.StringValue(entry.MessageGroupId),
+		)
+		if err != nil {
+			output.Failed = append(output.Failed, batchResultErrorEntry(entry.Id, err))
+			continue
+		}
+
+		result := &sqsLib.SendMessageBatchResultEntry{
+			Id:               entry.Id,
+			MD5OfMessageBody: aws.String(m.bodyMD5),
+			MessageId:        aws.String(m.id),
+		}
+
+		if m.sequenceNumber != "" {
+			result.SequenceNumber = aws.String(m.sequenceNumber)
+		}
+
+		output.Successful = append(output.Successful, result)
+	}
+
+	return output, nil
+}
+
+// DeleteMessageBatch deletes up to 10 messages, entries with invalid receipt
+// handles are returned as failed.
+func (f *Fake) DeleteMessageBatch(input *sqsLib.DeleteMessageBatchInput) (*sqsLib.DeleteMessageBatchOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	q, err := f.queue(input.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	entryIDs := make([]*string, 0, len(input.Entries))
+	for _, entry := range input.Entries {
+		entryIDs = append(entryIDs, entry.Id)
+	}
+
+	err = validateBatchEntryIDs(entryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &sqsLib.DeleteMessageBatchOutput{
+		Failed:     []*sqsLib.BatchResultErrorEntry{},
+		Successful: []*sqsLib.DeleteMessageBatchResultEntry{},
+	}
+
+	for _, entry := range input.Entries {
+		err := f.delete(q, aws.StringValue(entry.ReceiptHandle))
+		if err != nil {
+			output.Failed = append(output.Failed, batchResultErrorEntry(entry.Id, err))
+			continue
+		}
+
+		output.Successful = append(output.Successful, &sqsLib.DeleteMessageBatchResultEntry{
+			Id: entry.Id,
+		})
+	}
+
+	return output, nil
+}
+
+// ChangeMessageVisibilityBatch changes the visibility timeout of up to 10
+// in flight messages, entries that can't be changed are returned as failed.
+func (f *Fake) ChangeMessageVisibilityBatch(input *sqsLib.ChangeMessageVisibilityBatchInput) (*sqsLib.ChangeMessageVisibilityBatchOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	q, err := f.queue(input.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	entryIDs := make([]*string, 0, len(input.Entries))
+	for _, entry := range input.Entries {
+		entryIDs = append(entryIDs, entry.Id)
+	}
+
+	err = validateBatchEntryIDs(entryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &sqsLib.ChangeMessageVisibilityBatchOutput{
+		Failed:     []*sqsLib.BatchResultErrorEntry{},
+		Successful: []*sqsLib.ChangeMessageVisibilityBatchResultEntry{},
+	}
+
+	for _, entry := range input.Entries {
+		err := f.changeVisibility(q, aws.StringValue(entry.ReceiptHandle), aws.Int64Value(entry.VisibilityTimeout))
+		if err != nil {
+			output.Failed = append(output.Failed, batchResultErrorEntry(entry.Id, err))
+			continue
+		}
+
+		output.Successful = append(output.Successful, &sqsLib.ChangeMessageVisibilityBatchResultEntry{
+			Id: entry.Id,
+		})
+	}
+
+	return output, nil
+}
+
+func validateBatchEntryIDs(entryIDs []*string) error {
+	if len(entryIDs) == 0 {
+		return awserr.New(sqsLib.ErrCodeEmptyBatchRequest, "There should be at least one entry in the request.", nil)
+	}
+
+	if len(entryIDs) > maxBatchEntries {
+		return awserr.New(
+			sqsLib.ErrCodeTooManyEntriesInBatchRequest,
+			fmt.Sprintf("Maximum number of entries per request are %d. You have sent %d.", maxBatchEntries, len(entryIDs)),
+			nil,
+		)
+	}
+
+	seen := make(map[string]bool)
+	for _, entryID := range aws.StringValueSlice(entryIDs) {
+		if seen[entryID] {
+			return awserr.New(sqsLib.ErrCodeBatchEntryIdsNotDistinct, fmt.Sprintf("Id %s repeated.", entryID), nil)
+		}
+
+		seen[entryID] = true
+	}
+
+	return nil
+}
+
+func batchResultErrorEntry(entryID *string, err error) *sqsLib.BatchResultErrorEntry {
+	code := errCodeInvalidParameterValue
+	message := err.Error()
+
+	if awsErr, ok := err.(awserr.Error); ok {
+		code = awsErr.Code()
+		message = awsErr.Message()
+	}
+
+	return &sqsLib.BatchResultErrorEntry{
+		Code:        aws.String(code),
+		Id:          entryID,
+		Message:     aws.String(message),
+		SenderFault: aws.Bool(true),
+	}
+}
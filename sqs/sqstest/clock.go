@@ -0,0 +1,57 @@
+package sqstest
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// Clock provides the current time to the Fake, so visibility timeouts,
+	// delays and retention can be tested without waiting.
+	Clock interface {
+		Now() time.Time
+	}
+
+	// ManualClock is a Clock that only moves when told to.
+	ManualClock struct {
+		mutex sync.Mutex
+		now   time.Time
+	}
+
+	systemClock struct{}
+)
+
+// NewManualClock creates a new ManualClock set to the given time.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{
+		now: now,
+	}
+}
+
+// Now returns the current time of the clock.
+func (c *ManualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward by the given duration.
+func (c *ManualClock) Advance(duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(duration)
+}
+
+// Set moves the clock to the given time.
+func (c *ManualClock) Set(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = now
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,121 @@
+package sqstest
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// The WithContext variants return the context's error if it is already
+// done and otherwise behave as the operations without a context, the
+// request options are ignored.
+
+// CreateQueueWithContext is CreateQueue with a context.
+func (f *Fake) CreateQueueWithContext(ctx aws.Context, input *sqsLib.CreateQueueInput, opts ...request.Option) (*sqsLib.CreateQueueOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return f.CreateQueue(input)
+}
+
+// DeleteQueueWithContext is DeleteQueue with a context.
+func (f *Fake) DeleteQueueWithContext(ctx aws.Context, input *sqsLib.DeleteQueueInput, opts ...request.Option) (*sqsLib.DeleteQueueOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return f.DeleteQueue(input)
+}
+
+// PurgeQueueWithContext is PurgeQueue with a context.
+func (f *Fake) PurgeQueueWithContext(ctx aws.Context, input *sqsLib.PurgeQueueInput, opts ...request.Option) (*sqsLib.PurgeQueueOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return f.PurgeQueue(input)
+}
+
+// GetQueueUrlWithContext is GetQueueUrl with a context.
+func (f *Fake) GetQueueUrlWithContext(ctx aws.Context, input *sqsLib.GetQueueUrlInput, opts ...request.Option) (*sqsLib.GetQueueUrlOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return f.GetQueueUrl(input)
+}
+
+// GetQueueAttributesWithContext is GetQueueAttributes with a context.
+func (f *Fake) GetQueueAttributesWithContext(ctx aws.Context, input *sqsLib.GetQueueAttributesInput, opts ...request.Option) (*sqsLib.GetQueueAttributesOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return f.GetQueueAttributes(input)
+}
+
+// SetQueueAttributesWithContext is SetQueueAttributes with a context.
+func (f *Fake) SetQueueAttributesWithContext(ctx aws.Context, input *sqsLib.SetQueueAttributesInput, opts ...request.Option) (*sqsLib.SetQueueAttributesOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return f.SetQueueAttributes(input)
+}
+
+// SendMessageWithContext is SendMessage with a context.
+func (f *Fake) SendMessageWithContext(ctx aws.Context, input *sqsLib.SendMessageInput, opts ...request.Option) (*sqsLib.SendMessageOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return f.SendMessage(input)
+}
+
+// DeleteMessageWithContext is DeleteMessage with a context.
+func (f *Fake) DeleteMessageWithContext(ctx aws.Context, input *sqsLib.DeleteMessageInput, opts ...request.Option) (*sqsLib.DeleteMessageOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return f.DeleteMessage(input)
+}
+
+// ChangeMessageVisibilityWithContext is ChangeMessageVisibility with a
+// context.
+func (f *Fake) ChangeMessageVisibilityWithContext(ctx aws.Context, input *sqsLib.ChangeMessageVisibilityInput, opts ...request.Option) (*sqsLib.ChangeMessageVisibilityOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return f.ChangeMessageVisibility(input)
+}
+
+// SendMessageBatchWithContext is SendMessageBatch with a context.
+func (f *Fake) SendMessageBatchWithContext(ctx aws.Context, input *sqsLib.SendMessageBatchInput, opts ...request.Option) (*sqsLib.SendMessageBatchOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return f.SendMessageBatch(input)
+}
+
+// DeleteMessageBatchWithContext is DeleteMessageBatch with a context.
+func (f *Fake) DeleteMessageBatchWithContext(ctx aws.Context, input *sqsLib.DeleteMessageBatchInput, opts ...request.Option) (*sqsLib.DeleteMessageBatchOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return f.DeleteMessageBatch(input)
+}
+
+// ChangeMessageVisibilityBatchWithContext is ChangeMessageVisibilityBatch
+// with a context.
+func (f *Fake) ChangeMessageVisibilityBatchWithContext(ctx aws.Context, input *sqsLib.ChangeMessageVisibilityBatchInput, opts ...request.Option) (*sqsLib.ChangeMessageVisibilityBatchOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return f.ChangeMessageVisibilityBatch(input)
+}
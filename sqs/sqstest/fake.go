@@ -0,0 +1,576 @@
+// Package sqstest provides an in-memory implementation of sqsiface.SQSAPI,
+// modelling visibility timeouts, receipt handles, delays, dead letter queues
+// and FIFO ordering and deduplication, for testing code that uses SQS.
+package sqstest
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+)
+
+const (
+	// Endpoint is the endpoint used in the URLs of queues created by the Fake.
+	Endpoint = "http://sqstest.local"
+
+	// AccountID is the account ID used in the URLs and ARNs of queues created
+	// by the Fake.
+	AccountID = "000000000000"
+
+	// Region is the region used in the ARNs of queues created by the Fake.
+	Region = "eu-west-1"
+
+	defaultDelaySeconds           = "0"
+	defaultMessageRetentionPeriod = "345600"
+	defaultVisibilityTimeout      = "30"
+	deduplicationInterval         = 5 * time.Minute
+	errCodeInvalidParameterValue  = "InvalidParameterValue"
+	errCodeMissingParameter       = "MissingParameter"
+	fifoQueueSuffix               = ".fifo"
+	longPollPause                 = 10 * time.Millisecond
+	maxDelaySeconds               = 900
+	maxNumberOfMessages           = 10
+	maxVisibilityTimeout          = 43200
+)
+
+type (
+	// Fake is an in-memory SQS, only the operations used by this repository
+	// are implemented and calling any other operation panics. Long polling
+	// is shortened to a brief pause so that polling loops don't spin.
+	Fake struct {
+		sqsiface.SQSAPI
+		clock    Clock
+		mutex    sync.Mutex
+		queues   map[string]*queue
+		sequence int64
+	}
+)
+
+// NewFake creates a new Fake with no queues, clock is used for all time
+// based behaviour and defaults to the system clock when nil.
+func NewFake(clock Clock) *Fake {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &Fake{
+		clock:  clock,
+		queues: make(map[string]*queue),
+	}
+}
+
+// Messages returns every message stored in the given queue, including
+// those that are in flight or delayed, without receiving them.
+func (f *Fake) Messages(queueName string) []*sqsLib.Message {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	q, ok := f.queues[queueURL(queueName)]
+	if !ok {
+		return nil
+	}
+
+	q.expireMessages(f.clock.Now())
+
+	all := []*string{
+		aws.String(sqsLib.QueueAttributeNameAll),
+	}
+
+	messages := make([]*sqsLib.Message, 0, len(q.messages))
+	for _, m := range q.messages {
+		messages = append(messages, m.sqsMessage(all, all))
+	}
+
+	return messages
+}
+
+// CreateQueue creates a queue, returning the URL of the existing queue if it
+// already exists.
+func (f *Fake) CreateQueue(input *sqsLib.CreateQueueInput) (*sqsLib.CreateQueueOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	queueName := aws.StringValue(input.QueueName)
+	if queueName == "" {
+		return nil, awserr.New(errCodeMissingParameter, "The request must contain the parameter QueueName.", nil)
+	}
+
+	url := queueURL(queueName)
+	if _, ok := f.queues[url]; ok {
+		return &sqsLib.CreateQueueOutput{QueueUrl: aws.String(url)}, nil
+	}
+
+	attributes := map[string]string{
+		sqsLib.QueueAttributeNameDelaySeconds:           defaultDelaySeconds,
+		sqsLib.QueueAttributeNameMessageRetentionPeriod: defaultMessageRetentionPeriod,
+		sqsLib.QueueAttributeNameVisibilityTimeout:      defaultVisibilityTimeout,
+	}
+
+	for name, value := range aws.StringValueMap(input.Attributes) {
+		attributes[name] = value
+	}
+
+	fifo := strings.HasSuffix(queueName, fifoQueueSuffix)
+	if fifo != (attributes[sqsLib.QueueAttributeNameFifoQueue] == "true") {
+		return nil, awserr.New(
+			errCodeInvalidParameterValue,
+			"The name of a FIFO queue can only include alphanumeric characters, hyphens, or underscores, must end with .fifo suffix.",
+			nil,
+		)
+	}
+
+	f.queues[url] = &queue{
+		arn:              fmt.Sprintf("arn:aws:sqs:%s:%s:%s", Region, AccountID, queueName),
+		attributes:       attributes,
+		createdAt:        f.clock.Now(),
+		deduplicationIDs: make(map[string]deduplicatedMessage),
+		fifo:             fifo,
+		name:             queueName,
+		url:              url,
+	}
+
+	return &sqsLib.CreateQueueOutput{QueueUrl: aws.String(url)}, nil
+}
+
+// DeleteQueue deletes a queue and all of its messages.
+func (f *Fake) DeleteQueue(input *sqsLib.DeleteQueueInput) (*sqsLib.DeleteQueueOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	q, err := f.queue(input.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(f.queues, q.url)
+
+	return &sqsLib.DeleteQueueOutput{}, nil
+}
+
+// PurgeQueue deletes all of the messages in a queue.
+func (f *Fake) PurgeQueue(input *sqsLib.PurgeQueueInput) (*sqsLib.PurgeQueueOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	q, err := f.queue(input.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	q.messages = nil
+
+	return &sqsLib.PurgeQueueOutput{}, nil
+}
+
+// GetQueueUrl returns the URL of a queue created by the Fake.
+func (f *Fake) GetQueueUrl(input *sqsLib.GetQueueUrlInput) (*sqsLib.GetQueueUrlOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	q, err := f.queue(aws.String(queueURL(aws.StringValue(input.QueueName))))
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqsLib.GetQueueUrlOutput{QueueUrl: aws.String(q.url)}, nil
+}
+
+// GetQueueAttributes returns the requested attributes of a queue, including
+// the approximate message counts.
+func (f *Fake) GetQueueAttributes(input *sqsLib.GetQueueAttributesInput) (*sqsLib.GetQueueAttributesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	q, err := f.queue(input.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	now := f.clock.Now()
+	q.expireMessages(now)
+
+	attributes := make(map[string]*string)
+	for name, value := range q.queueAttributes(now) {
+		if nameRequested(name, input.AttributeNames) {
+			attributes[name] = aws.String(value)
+		}
+	}
+
+	return &sqsLib.GetQueueAttributesOutput{Attributes: attributes}, nil
+}
+
+// SetQueueAttributes updates the attributes of a queue.
+func (f *Fake) SetQueueAttributes(input *sqsLib.SetQueueAttributesInput) (*sqsLib.SetQueueAttributesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	q, err := f.queue(input.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := aws.StringValueMap(input.Attributes)
+	if _, ok := attributes[sqsLib.QueueAttributeNameFifoQueue]; ok {
+		return nil, awserr.New(sqsLib.ErrCodeInvalidAttributeName, "Unknown Attribute FifoQueue.", nil)
+	}
+
+	for name, value := range attributes {
+		q.attributes[name] = value
+	}
+
+	return &sqsLib.SetQueueAttributesOutput{}, nil
+}
+
+// SendMessage adds a message to a queue.
+func (f *Fake) SendMessage(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	q, err := f.queue(input.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := f.send(
+		q,
+		aws.StringValue(input.MessageBody),
+		input.DelaySeconds,
+		input.MessageAttributes,
+		aws.StringValue(input.MessageDeduplicationId),
+		aws.StringValue(input.MessageGroupId),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &sqsLib.SendMessageOutput{
+		MD5OfMessageBody: aws.String(m.bodyMD5),
+		MessageId:        aws.String(m.id),
+	}
+
+	if m.sequenceNumber != "" {
+		output.SequenceNumber = aws.String(m.sequenceNumber)
+	}
+
+	return output, nil
+}
+
+// ReceiveMessage receives visible messages from a queue, hiding them for
+// the visibility timeout. Messages in FIFO queues are received in order and
+// no message is received from a group with a message in flight.
+func (f *Fake) ReceiveMessage(input *sqsLib.ReceiveMessageInput) (*sqsLib.ReceiveMessageOutput, error) {
+	return f.ReceiveMessageWithContext(context.Background(), input)
+}
+
+// ReceiveMessageWithContext is ReceiveMessage with a context, cancelling the
+// context ends the long poll pause early.
+func (f *Fake) ReceiveMessageWithContext(ctx aws.Context, input *sqsLib.ReceiveMessageInput, opts ...request.Option) (*sqsLib.ReceiveMessageOutput, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	messages, err := f.receive(input)
+	if err == nil && len(messages) == 0 && aws.Int64Value(input.WaitTimeSeconds) > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(longPollPause):
+		}
+
+		messages, err = f.receive(input)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqsLib.ReceiveMessageOutput{Messages: messages}, nil
+}
+
+// DeleteMessage deletes the message with the given receipt handle, only the
+// handle from the most recent receive of a message is valid.
+func (f *Fake) DeleteMessage(input *sqsLib.DeleteMessageInput) (*sqsLib.DeleteMessageOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	q, err := f.queue(input.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.delete(q, aws.StringValue(input.ReceiptHandle))
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqsLib.DeleteMessageOutput{}, nil
+}
+
+// ChangeMessageVisibility sets the visibility timeout of an in flight
+// message to the given number of seconds from now.
+func (f *Fake) ChangeMessageVisibility(input *sqsLib.ChangeMessageVisibilityInput) (*sqsLib.ChangeMessageVisibilityOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	q, err := f.queue(input.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.changeVisibility(q, aws.StringValue(input.ReceiptHandle), aws.Int64Value(input.VisibilityTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqsLib.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (f *Fake) queue(url *string) (*queue, error) {
+	q, ok := f.queues[aws.StringValue(url)]
+	if !ok {
+		return nil, awserr.New(sqsLib.ErrCodeQueueDoesNotExist, "The specified queue does not exist for this wsdl version.", nil)
+	}
+
+	return q, nil
+}
+
+func (f *Fake) queueByARN(arn string) *queue {
+	for _, q := range f.queues {
+		if q.arn == arn {
+			return q
+		}
+	}
+
+	return nil
+}
+
+func (f *Fake) nextSequence() int64 {
+	f.sequence++
+	return f.sequence
+}
+
+func (f *Fake) send(q *queue, body string, delaySeconds *int64, attributes map[string]*sqsLib.MessageAttributeValue, deduplicationID string, groupID string) (*message, error) {
+	if body == "" {
+		return nil, awserr.New(errCodeMissingParameter, "The request must contain the parameter MessageBody.", nil)
+	}
+
+	now := f.clock.Now()
+	q.expireMessages(now)
+
+	delay := q.integerAttribute(sqsLib.QueueAttributeNameDelaySeconds)
+	if delaySeconds != nil {
+		if q.fifo {
+			return nil, awserr.New(errCodeInvalidParameterValue, "The request include parameter that is not valid for this queue type.", nil)
+		}
+
+		delay = *delaySeconds
+	}
+
+	if delay < 0 || delay > maxDelaySeconds {
+		return nil, awserr.New(
+			errCodeInvalidParameterValue,
+			fmt.Sprintf("Value %d for parameter DelaySeconds is invalid. Reason: must be between 0 and %d.", delay, maxDelaySeconds),
+			nil,
+		)
+	}
+
+	bodyMD5 := md5.Sum([]byte(body))
+
+	m := &message{
+		attributes: attributes,
+		body:       body,
+		bodyMD5:    hex.EncodeToString(bodyMD5[:]),
+		sentAt:     now,
+		visibleAt:  now.Add(time.Duration(delay) * time.Second),
+	}
+
+	if q.fifo {
+		if groupID == "" {
+			return nil, awserr.New(errCodeMissingParameter, "The request must contain the parameter MessageGroupId.", nil)
+		}
+
+		if deduplicationID == "" && q.attributes[sqsLib.QueueAttributeNameContentBasedDeduplication] == "true" {
+			bodySHA256 := sha256.Sum256([]byte(body))
+			deduplicationID = hex.EncodeToString(bodySHA256[:])
+		}
+
+		if deduplicationID == "" {
+			return nil, awserr.New(
+				errCodeInvalidParameterValue,
+				"The queue should either have ContentBasedDeduplication enabled or MessageDeduplicationId provided explicitly",
+				nil,
+			)
+		}
+
+		if deduplicated, ok := q.deduplicationIDs[deduplicationID]; ok {
+			m.id = deduplicated.id
+			m.sequenceNumber = deduplicated.sequenceNumber
+			return m, nil
+		}
+
+		m.deduplicationID = deduplicationID
+		m.groupID = groupID
+		m.sequenceNumber = fmt.Sprintf("%020d", f.nextSequence())
+	}
+
+	m.id = fmt.Sprintf("00000000-0000-4000-8000-%012x", f.nextSequence())
+
+	if q.fifo {
+		q.deduplicationIDs[deduplicationID] = deduplicatedMessage{
+			expiresAt:      now.Add(deduplicationInterval),
+			id:             m.id,
+			sequenceNumber: m.sequenceNumber,
+		}
+	}
+
+	q.messages = append(q.messages, m)
+
+	return m, nil
+}
+
+func (f *Fake) receive(input *sqsLib.ReceiveMessageInput) ([]*sqsLib.Message, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	q, err := f.queue(input.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	maxMessages := aws.Int64Value(input.MaxNumberOfMessages)
+	if input.MaxNumberOfMessages == nil {
+		maxMessages = 1
+	}
+
+	if maxMessages < 1 || maxMessages > maxNumberOfMessages {
+		return nil, awserr.New(
+			errCodeInvalidParameterValue,
+			fmt.Sprintf("Value %d for parameter MaxNumberOfMessages is invalid. Reason: must be between 1 and %d.", maxMessages, maxNumberOfMessages),
+			nil,
+		)
+	}
+
+	visibilityTimeout := q.integerAttribute(sqsLib.QueueAttributeNameVisibilityTimeout)
+	if input.VisibilityTimeout != nil {
+		visibilityTimeout = *input.VisibilityTimeout
+	}
+
+	now := f.clock.Now()
+	q.expireMessages(now)
+
+	blockedGroups := make(map[string]bool)
+	if q.fifo {
+		for _, m := range q.messages {
+			if m.receiveCount > 0 && m.visibleAt.After(now) {
+				blockedGroups[m.groupID] = true
+			}
+		}
+	}
+
+	candidates := append([]*message(nil), q.messages...)
+	messages := make([]*sqsLib.Message, 0, maxMessages)
+
+	for _, m := range candidates {
+		if int64(len(messages)) == maxMessages {
+			break
+		}
+
+		if m.visibleAt.After(now) || blockedGroups[m.groupID] {
+			continue
+		}
+
+		if f.moveToDeadLetterQueue(q, m, now) {
+			continue
+		}
+
+		if m.firstReceivedAt.IsZero() {
+			m.firstReceivedAt = now
+		}
+
+		m.receiveCount++
+		m.receiptHandle = fmt.Sprintf("%s#%d", m.id, f.nextSequence())
+		m.visibleAt = now.Add(time.Duration(visibilityTimeout) * time.Second)
+
+		messages = append(messages, m.sqsMessage(input.AttributeNames, input.MessageAttributeNames))
+	}
+
+	return messages, nil
+}
+
+func (f *Fake) moveToDeadLetterQueue(q *queue, m *message, now time.Time) bool {
+	deadLetterTargetARN, maxReceiveCount := q.redrivePolicy()
+	if maxReceiveCount < 1 || m.receiveCount < maxReceiveCount {
+		return false
+	}
+
+	deadLetterQueue := f.queueByARN(deadLetterTargetARN)
+	if deadLetterQueue == nil {
+		return false
+	}
+
+	q.removeMessage(m)
+
+	m.receiptHandle = ""
+	m.visibleAt = now
+	deadLetterQueue.messages = append(deadLetterQueue.messages, m)
+
+	return true
+}
+
+func (f *Fake) delete(q *queue, receiptHandle string) error {
+	m := q.messageByReceiptHandle(receiptHandle)
+	if m == nil {
+		return awserr.New(
+			sqsLib.ErrCodeReceiptHandleIsInvalid,
+			fmt.Sprintf("The input receipt handle \"%s\" is not a valid receipt handle.", receiptHandle),
+			nil,
+		)
+	}
+
+	q.removeMessage(m)
+
+	return nil
+}
+
+func (f *Fake) changeVisibility(q *queue, receiptHandle string, visibilityTimeout int64) error {
+	m := q.messageByReceiptHandle(receiptHandle)
+	if m == nil {
+		return awserr.New(
+			sqsLib.ErrCodeReceiptHandleIsInvalid,
+			fmt.Sprintf("The input receipt handle \"%s\" is not a valid receipt handle.", receiptHandle),
+			nil,
+		)
+	}
+
+	if visibilityTimeout < 0 || visibilityTimeout > maxVisibilityTimeout {
+		return awserr.New(
+			errCodeInvalidParameterValue,
+			fmt.Sprintf("Value %d for parameter VisibilityTimeout is invalid. Reason: must be between 0 and %d.", visibilityTimeout, maxVisibilityTimeout),
+			nil,
+		)
+	}
+
+	now := f.clock.Now()
+	if !m.visibleAt.After(now) {
+		return awserr.New(sqsLib.ErrCodeMessageNotInflight, "The message referred to isn't in flight.", nil)
+	}
+
+	m.visibleAt = now.Add(time.Duration(visibilityTimeout) * time.Second)
+
+	return nil
+}
+
+func queueURL(queueName string) string {
+	return fmt.Sprintf("%s/%s/%s", Endpoint, AccountID, queueName)
+}
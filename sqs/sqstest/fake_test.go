@@ -0,0 +1,328 @@
+package sqstest_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers/sqs"
+	"github.com/vidsy/awswrappers/sqs/sqstest"
+)
+
+func NewTestClient(t *testing.T, fake *sqstest.Fake, queueName string, options sqs.QueueOptions) *sqs.Client {
+	client := sqs.NewClient(
+		&sqs.ClientConfig{
+			QueueEndpoint:       sqstest.Endpoint,
+			MaxNumberOfMessages: 10,
+			VisibilityTimeout:   30,
+			WaitTimeSeconds:     20,
+		},
+		false,
+		fake,
+	)
+
+	_, err := client.EnsureQueue(queueName, options)
+	assert.NoError(t, err)
+
+	return client
+}
+
+func receiveMessages(t *testing.T, fake *sqstest.Fake, client *sqs.Client, queueName string, maxNumberOfMessages int64) []*sqsLib.Message {
+	queueURL, err := client.QueueURL(queueName)
+	assert.NoError(t, err)
+
+	resp, err := fake.ReceiveMessage(&sqsLib.ReceiveMessageInput{
+		MaxNumberOfMessages: aws.Int64(maxNumberOfMessages),
+		QueueUrl:            aws.String(queueURL),
+	})
+	assert.NoError(t, err)
+
+	return resp.Messages
+}
+
+func queueAttribute(t *testing.T, fake *sqstest.Fake, client *sqs.Client, queueName string, attributeName string) string {
+	queueURL, err := client.QueueURL(queueName)
+	assert.NoError(t, err)
+
+	resp, err := fake.GetQueueAttributes(&sqsLib.GetQueueAttributesInput{
+		AttributeNames: []*string{aws.String(attributeName)},
+		QueueUrl:       aws.String(queueURL),
+	})
+	assert.NoError(t, err)
+
+	return aws.StringValue(resp.Attributes[attributeName])
+}
+
+func messageBodies(messages []*sqsLib.Message) []string {
+	var bodies []string
+	for _, message := range messages {
+		bodies = append(bodies, *message.Body)
+	}
+
+	return bodies
+}
+
+func TestFake(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("ReceivedMessagesAreHiddenUntilVisibilityTimeout", func(t *testing.T) {
+		clock := sqstest.NewManualClock(start)
+		fake := sqstest.NewFake(clock)
+		client := NewTestClient(t, fake, "foo", sqs.QueueOptions{})
+
+		_, err := client.SendNewMessage("foo", []byte("body"))
+		assert.NoError(t, err)
+
+		first, err := client.ReceiveMessage("foo")
+		assert.NoError(t, err)
+		assert.Equal(t, "body", *first.Body)
+		assert.Equal(t, "1", *first.Attributes["ApproximateReceiveCount"])
+
+		message, err := client.ReceiveMessage("foo")
+		assert.NoError(t, err)
+		assert.Nil(t, message)
+
+		clock.Advance(30 * time.Second)
+
+		second, err := client.ReceiveMessage("foo")
+		assert.NoError(t, err)
+		assert.Equal(t, *first.MessageId, *second.MessageId)
+		assert.Equal(t, "2", *second.Attributes["ApproximateReceiveCount"])
+		assert.NotEqual(t, *first.ReceiptHandle, *second.ReceiptHandle)
+	})
+
+	t.Run("DeleteRequiresLatestReceiptHandle", func(t *testing.T) {
+		clock := sqstest.NewManualClock(start)
+		fake := sqstest.NewFake(clock)
+		client := NewTestClient(t, fake, "foo", sqs.QueueOptions{})
+
+		_, err := client.SendNewMessage("foo", []byte("body"))
+		assert.NoError(t, err)
+
+		first, err := client.ReceiveMessage("foo")
+		assert.NoError(t, err)
+
+		clock.Advance(30 * time.Second)
+
+		second, err := client.ReceiveMessage("foo")
+		assert.NoError(t, err)
+
+		err = client.DeleteMessage("foo", first.ReceiptHandle)
+		assert.Equal(t, sqsLib.ErrCodeReceiptHandleIsInvalid, err.(awserr.Error).Code())
+
+		err = client.DeleteMessage("foo", second.ReceiptHandle)
+		assert.NoError(t, err)
+		assert.Empty(t, fake.Messages("foo"))
+	})
+
+	t.Run("ChangeVisibilityTimeoutExtendsInFlightMessage", func(t *testing.T) {
+		clock := sqstest.NewManualClock(start)
+		fake := sqstest.NewFake(clock)
+		client := NewTestClient(t, fake, "foo", sqs.QueueOptions{})
+
+		_, err := client.SendNewMessage("foo", []byte("body"))
+		assert.NoError(t, err)
+
+		message, err := client.ReceiveMessage("foo")
+		assert.NoError(t, err)
+
+		err = client.ChangeVisibilityTimeout("foo", message.ReceiptHandle, 120)
+		assert.NoError(t, err)
+
+		clock.Advance(60 * time.Second)
+
+		received, err := client.ReceiveMessage("foo")
+		assert.NoError(t, err)
+		assert.Nil(t, received)
+
+		clock.Advance(60 * time.Second)
+
+		err = client.ChangeVisibilityTimeout("foo", message.ReceiptHandle, 120)
+		assert.Equal(t, sqsLib.ErrCodeMessageNotInflight, err.(awserr.Error).Code())
+	})
+
+	t.Run("DelayedMessagesBecomeVisibleAfterDelay", func(t *testing.T) {
+		clock := sqstest.NewManualClock(start)
+		fake := sqstest.NewFake(clock)
+		client := NewTestClient(t, fake, "foo", sqs.QueueOptions{})
+
+		queueURL, err := client.QueueURL("foo")
+		assert.NoError(t, err)
+
+		_, err = fake.SendMessage(&sqsLib.SendMessageInput{
+			DelaySeconds: aws.Int64(60),
+			MessageBody:  aws.String("body"),
+			QueueUrl:     aws.String(queueURL),
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "1", queueAttribute(t, fake, client, "foo", "ApproximateNumberOfMessagesDelayed"))
+		assert.Empty(t, receiveMessages(t, fake, client, "foo", 10))
+
+		clock.Advance(60 * time.Second)
+
+		assert.Equal(t, "1", queueAttribute(t, fake, client, "foo", "ApproximateNumberOfMessages"))
+		assert.Equal(t, []string{"body"}, messageBodies(receiveMessages(t, fake, client, "foo", 10)))
+		assert.Equal(t, "1", queueAttribute(t, fake, client, "foo", "ApproximateNumberOfMessagesNotVisible"))
+	})
+
+	t.Run("BatchOperations", func(t *testing.T) {
+		fake := sqstest.NewFake(sqstest.NewManualClock(start))
+		client := NewTestClient(t, fake, "foo", sqs.QueueOptions{})
+
+		bodies := make([][]byte, 15)
+		for i := range bodies {
+			bodies[i] = []byte("body")
+		}
+
+		report, err := client.SendMessageBatch("foo", bodies)
+		assert.NoError(t, err)
+		assert.Len(t, report.Successful, 15)
+		assert.Len(t, fake.Messages("foo"), 15)
+
+		messages, err := client.ReceiveMessages("foo")
+		assert.NoError(t, err)
+		assert.Len(t, messages, 10)
+
+		receiptHandles := []*string{aws.String("invalid")}
+		for _, message := range messages {
+			receiptHandles = append(receiptHandles, message.ReceiptHandle)
+		}
+
+		report, err = client.DeleteMessageBatch("foo", receiptHandles)
+		assert.NoError(t, err)
+		assert.Len(t, report.Successful, 10)
+		assert.Len(t, report.Failed, 1)
+		assert.Equal(t, sqsLib.ErrCodeReceiptHandleIsInvalid, report.Failed[0].Code)
+		assert.Len(t, fake.Messages("foo"), 5)
+	})
+
+	t.Run("FIFOGroupsAreReceivedInOrder", func(t *testing.T) {
+		fake := sqstest.NewFake(sqstest.NewManualClock(start))
+		client := NewTestClient(t, fake, "foo.fifo", sqs.QueueOptions{})
+
+		for _, message := range []struct {
+			body    string
+			groupID string
+		}{
+			{"a1", "a"},
+			{"b1", "b"},
+			{"a2", "a"},
+			{"b2", "b"},
+		} {
+			_, err := client.SendNewFIFOMessage("foo.fifo", []byte(message.body), message.body, message.groupID, nil)
+			assert.NoError(t, err)
+		}
+
+		first := receiveMessages(t, fake, client, "foo.fifo", 1)
+		assert.Equal(t, []string{"a1"}, messageBodies(first))
+		assert.Equal(t, []string{"b1"}, messageBodies(receiveMessages(t, fake, client, "foo.fifo", 1)))
+		assert.Empty(t, receiveMessages(t, fake, client, "foo.fifo", 10))
+
+		err := client.DeleteMessage("foo.fifo", first[0].ReceiptHandle)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"a2"}, messageBodies(receiveMessages(t, fake, client, "foo.fifo", 10)))
+	})
+
+	t.Run("FIFODeduplicatesWithinInterval", func(t *testing.T) {
+		clock := sqstest.NewManualClock(start)
+		fake := sqstest.NewFake(clock)
+		client := NewTestClient(t, fake, "foo.fifo", sqs.QueueOptions{ContentBasedDeduplication: true})
+
+		firstID, err := client.SendNewFIFOMessage("foo.fifo", []byte("body"), "", "group", nil)
+		assert.NoError(t, err)
+
+		secondID, err := client.SendNewFIFOMessage("foo.fifo", []byte("body"), "", "group", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, firstID, secondID)
+		assert.Len(t, fake.Messages("foo.fifo"), 1)
+
+		clock.Advance(5 * time.Minute)
+
+		_, err = client.SendNewFIFOMessage("foo.fifo", []byte("body"), "", "group", nil)
+		assert.NoError(t, err)
+		assert.Len(t, fake.Messages("foo.fifo"), 2)
+	})
+
+	t.Run("MessagesMoveToDeadLetterQueueAfterMaxReceiveCount", func(t *testing.T) {
+		clock := sqstest.NewManualClock(start)
+		fake := sqstest.NewFake(clock)
+		NewTestClient(t, fake, "foo-dlq", sqs.QueueOptions{})
+		client := NewTestClient(t, fake, "foo", sqs.QueueOptions{
+			DeadLetterQueueName: "foo-dlq",
+			MaxReceiveCount:     2,
+		})
+
+		_, err := client.SendNewMessage("foo", []byte("body"))
+		assert.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			message, err := client.ReceiveMessage("foo")
+			assert.NoError(t, err)
+			assert.NotNil(t, message)
+
+			clock.Advance(30 * time.Second)
+		}
+
+		message, err := client.ReceiveMessage("foo")
+		assert.NoError(t, err)
+		assert.Nil(t, message)
+		assert.Len(t, fake.Messages("foo-dlq"), 1)
+
+		report, err := client.RedriveMessages("foo-dlq", "foo", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Moved)
+		assert.Len(t, fake.Messages("foo"), 1)
+		assert.Empty(t, fake.Messages("foo-dlq"))
+	})
+
+	t.Run("CancelledContextEndsLongPoll", func(t *testing.T) {
+		fake := sqstest.NewFake(nil)
+		client := NewTestClient(t, fake, "foo", sqs.QueueOptions{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.ReceiveMessagesWithContext(ctx, "foo")
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("ConsumerHandlesAllMessages", func(t *testing.T) {
+		fake := sqstest.NewFake(nil)
+		client := NewTestClient(t, fake, "foo", sqs.QueueOptions{})
+
+		for i := 0; i < 25; i++ {
+			_, err := client.SendNewMessage("foo", []byte("body"))
+			assert.NoError(t, err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var mutex sync.Mutex
+		handled := 0
+
+		consumer := sqs.NewConsumer(client, "foo", 3, func(ctx context.Context, message *sqsLib.Message) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			handled++
+			if handled == 25 {
+				cancel()
+			}
+
+			return nil
+		}, func(err error) {
+			t.Error(err)
+		})
+
+		consumer.Start(ctx)
+
+		assert.Equal(t, 25, handled)
+		assert.Empty(t, fake.Messages("foo"))
+	})
+}
@@ -0,0 +1,210 @@
+package sqstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+)
+
+type (
+	queue struct {
+		arn              string
+		attributes       map[string]string
+		createdAt        time.Time
+		deduplicationIDs map[string]deduplicatedMessage
+		fifo             bool
+		messages         []*message
+		name             string
+		url              string
+	}
+
+	message struct {
+		attributes      map[string]*sqsLib.MessageAttributeValue
+		body            string
+		bodyMD5         string
+		deduplicationID string
+		firstReceivedAt time.Time
+		groupID         string
+		id              string
+		receiptHandle   string
+		receiveCount    int64
+		sentAt          time.Time
+		sequenceNumber  string
+		visibleAt       time.Time
+	}
+
+	deduplicatedMessage struct {
+		expiresAt      time.Time
+		id             string
+		sequenceNumber string
+	}
+
+	redrivePolicy struct {
+		DeadLetterTargetArn string      `json:"deadLetterTargetArn"`
+		MaxReceiveCount     interface{} `json:"maxReceiveCount"`
+	}
+)
+
+func (q *queue) integerAttribute(name string) int64 {
+	value, _ := strconv.ParseInt(q.attributes[name], 10, 64)
+	return value
+}
+
+func (q *queue) redrivePolicy() (string, int64) {
+	var policy redrivePolicy
+
+	if json.Unmarshal([]byte(q.attributes[sqsLib.QueueAttributeNameRedrivePolicy]), &policy) != nil {
+		return "", 0
+	}
+
+	maxReceiveCount, _ := strconv.ParseInt(fmt.Sprint(policy.MaxReceiveCount), 10, 64)
+
+	return policy.DeadLetterTargetArn, maxReceiveCount
+}
+
+func (q *queue) expireMessages(now time.Time) {
+	retentionPeriod := time.Duration(q.integerAttribute(sqsLib.QueueAttributeNameMessageRetentionPeriod)) * time.Second
+	messages := q.messages[:0]
+
+	for _, m := range q.messages {
+		if now.Before(m.sentAt.Add(retentionPeriod)) {
+			messages = append(messages, m)
+		}
+	}
+
+	q.messages = messages
+
+	for deduplicationID, deduplicated := range q.deduplicationIDs {
+		if !now.Before(deduplicated.expiresAt) {
+			delete(q.deduplicationIDs, deduplicationID)
+		}
+	}
+}
+
+func (q *queue) removeMessage(target *message) {
+	for i, m := range q.messages {
+		if m == target {
+			q.messages = append(q.messages[:i], q.messages[i+1:]...)
+			return
+		}
+	}
+}
+
+func (q *queue) messageByReceiptHandle(receiptHandle string) *message {
+	if receiptHandle == "" {
+		return nil
+	}
+
+	for _, m := range q.messages {
+		if m.receiptHandle == receiptHandle {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func (q *queue) queueAttributes(now time.Time) map[string]string {
+	attributes := map[string]string{
+		sqsLib.QueueAttributeNameCreatedTimestamp: strconv.FormatInt(q.createdAt.Unix(), 10),
+		sqsLib.QueueAttributeNameQueueArn:         q.arn,
+	}
+
+	for name, value := range q.attributes {
+		attributes[name] = value
+	}
+
+	var visible, notVisible, delayed int
+
+	for _, m := range q.messages {
+		switch {
+		case !m.visibleAt.After(now):
+			visible++
+		case m.receiveCount > 0:
+			notVisible++
+		default:
+			delayed++
+		}
+	}
+
+	attributes[sqsLib.QueueAttributeNameApproximateNumberOfMessages] = strconv.Itoa(visible)
+	attributes[sqsLib.QueueAttributeNameApproximateNumberOfMessagesNotVisible] = strconv.Itoa(notVisible)
+	attributes[sqsLib.QueueAttributeNameApproximateNumberOfMessagesDelayed] = strconv.Itoa(delayed)
+
+	return attributes
+}
+
+func (m *message) sqsMessage(attributeNames []*string, messageAttributeNames []*string) *sqsLib.Message {
+	attributes := map[string]string{
+		sqsLib.MessageSystemAttributeNameApproximateReceiveCount: strconv.FormatInt(m.receiveCount, 10),
+		sqsLib.MessageSystemAttributeNameSentTimestamp:           strconv.FormatInt(timestamp(m.sentAt), 10),
+	}
+
+	if !m.firstReceivedAt.IsZero() {
+		attributes[sqsLib.MessageSystemAttributeNameApproximateFirstReceiveTimestamp] = strconv.FormatInt(timestamp(m.firstReceivedAt), 10)
+	}
+
+	if m.sequenceNumber != "" {
+		attributes[sqsLib.MessageSystemAttributeNameMessageDeduplicationId] = m.deduplicationID
+		attributes[sqsLib.MessageSystemAttributeNameMessageGroupId] = m.groupID
+		attributes[sqsLib.MessageSystemAttributeNameSequenceNumber] = m.sequenceNumber
+	}
+
+	sqsMessage := &sqsLib.Message{
+		Body:      aws.String(m.body),
+		MD5OfBody: aws.String(m.bodyMD5),
+		MessageId: aws.String(m.id),
+	}
+
+	if m.receiptHandle != "" {
+		sqsMessage.ReceiptHandle = aws.String(m.receiptHandle)
+	}
+
+	for name, value := range attributes {
+		if nameRequested(name, attributeNames) {
+			if sqsMessage.Attributes == nil {
+				sqsMessage.Attributes = make(map[string]*string)
+			}
+
+			sqsMessage.Attributes[name] = aws.String(value)
+		}
+	}
+
+	for name, value := range m.attributes {
+		if nameRequested(name, messageAttributeNames) {
+			if sqsMessage.MessageAttributes == nil {
+				sqsMessage.MessageAttributes = make(map[string]*sqsLib.MessageAttributeValue)
+			}
+
+			sqsMessage.MessageAttributes[name] = value
+		}
+	}
+
+	return sqsMessage
+}
+
+func nameRequested(name string, requestedNames []*string) bool {
+	for _, requestedName := range aws.StringValueSlice(requestedNames) {
+		switch {
+		case requestedName == sqsLib.QueueAttributeNameAll || requestedName == ".*":
+			return true
+		case strings.HasSuffix(requestedName, ".*"):
+			if strings.HasPrefix(name, strings.TrimSuffix(requestedName, "*")) {
+				return true
+			}
+		case requestedName == name:
+			return true
+		}
+	}
+
+	return false
+}
+
+func timestamp(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,53 @@
+package sqs
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+)
+
+const (
+	maxMessageAttributes = 10
+)
+
+// MessageContext returns a context holding the trace context propagated in
+// the message attributes by the Client that sent the message, so spans
+// started while handling the message join the sender's trace.
+func (s Client) MessageContext(ctx context.Context, message *sqsLib.Message) context.Context {
+	carrier := map[string]string{}
+	for name, value := range message.MessageAttributes {
+		if value != nil && value.StringValue != nil {
+			carrier[name] = *value.StringValue
+		}
+	}
+
+	return s.tracer.Extract(ctx, carrier)
+}
+
+// injectTraceContext returns a copy of the message attributes with the trace
+// context of the span in the context added, the attributes are returned
+// unchanged if there is no trace context or adding it would exceed the
+// SQS limit of 10 attributes.
+func (s Client) injectTraceContext(ctx context.Context, messageAttributes map[string]*sqsLib.MessageAttributeValue) map[string]*sqsLib.MessageAttributeValue {
+	carrier := map[string]string{}
+	s.tracer.Inject(ctx, carrier)
+
+	if len(carrier) == 0 || len(messageAttributes)+len(carrier) > maxMessageAttributes {
+		return messageAttributes
+	}
+
+	tracedAttributes := make(map[string]*sqsLib.MessageAttributeValue, len(messageAttributes)+len(carrier))
+	for name, value := range messageAttributes {
+		tracedAttributes[name] = value
+	}
+
+	for name, value := range carrier {
+		tracedAttributes[name] = &sqsLib.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+
+	return tracedAttributes
+}
@@ -0,0 +1,150 @@
+package sqs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sqsLib "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers"
+	"github.com/vidsy/awswrappers/sqs"
+	"github.com/vidsy/awswrappers/sqs/sqstest"
+)
+
+func TestTracing(t *testing.T) {
+	t.Run(".SendNewMessage()", func(t *testing.T) {
+		t.Run("PropagatesTraceContextToConsumer", func(t *testing.T) {
+			tracer := awswrappers.NewInMemoryTracer()
+			client := sqs.NewClientWithOptions(
+				sqs.WithAPI(sqstest.NewFake(nil)),
+				sqs.WithClientConfig(&sqs.ClientConfig{
+					MaxNumberOfMessages: 10,
+					VisibilityTimeout:   30,
+				}),
+				sqs.WithTracer(tracer),
+			)
+
+			_, err := client.EnsureQueue("foo", sqs.QueueOptions{})
+			assert.NoError(t, err)
+
+			messageID, err := client.SendNewMessage("foo", []byte("body"))
+			assert.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			consumer := sqs.NewConsumer(client, "foo", 1, func(ctx context.Context, message *sqsLib.Message) error {
+				cancel()
+				return nil
+			}, func(err error) {
+				t.Error(err)
+			})
+			consumer.Start(ctx)
+
+			spansByName := map[string]awswrappers.InMemorySpan{}
+			for _, span := range tracer.Spans() {
+				spansByName[span.Name] = span
+			}
+
+			sendSpan := spansByName["sqs.SendNewMessage"]
+			handleSpan := spansByName["sqs.HandleMessage"]
+
+			assert.Equal(t, "foo", sendSpan.Attributes["queue"])
+			assert.Equal(t, messageID, sendSpan.Attributes["message_id"])
+			assert.Equal(t, sendSpan.TraceID, handleSpan.TraceID)
+			assert.Equal(t, sendSpan.ID, handleSpan.ParentID)
+			assert.True(t, handleSpan.Ended)
+		})
+
+		t.Run("LeavesAttributesUnchangedWithoutTracer", func(t *testing.T) {
+			var sentInput *sqsLib.SendMessageInput
+
+			mock := MockSDKClient{
+				mockSendMessage: func(input *sqsLib.SendMessageInput) (*sqsLib.SendMessageOutput, error) {
+					sentInput = input
+					return &sqsLib.SendMessageOutput{MessageId: aws.String("1")}, nil
+				},
+			}
+
+			_, err := NewTestClient(&mock).SendNewMessage("foo", []byte("body"))
+
+			assert.NoError(t, err)
+			assert.Empty(t, sentInput.MessageAttributes)
+		})
+	})
+
+	t.Run(".SendJSON()", func(t *testing.T) {
+		t.Run("PropagatesTraceContextWithMessageType", func(t *testing.T) {
+			tracer := awswrappers.NewInMemoryTracer()
+			client := sqs.NewClientWithOptions(
+				sqs.WithAPI(sqstest.NewFake(nil)),
+				sqs.WithClientConfig(&sqs.ClientConfig{
+					MaxNumberOfMessages: 10,
+					VisibilityTimeout:   30,
+				}),
+				sqs.WithTracer(tracer),
+			)
+
+			_, err := client.EnsureQueue("foo", sqs.QueueOptions{})
+			assert.NoError(t, err)
+
+			_, err = client.SendJSON("foo", "bar", map[string]string{"baz": "qux"})
+			assert.NoError(t, err)
+
+			message, err := client.ReceiveMessage("foo")
+			assert.NoError(t, err)
+
+			_, span := tracer.StartSpan(client.MessageContext(context.Background(), message), "handle")
+			span.End()
+
+			spansByName := map[string]awswrappers.InMemorySpan{}
+			for _, span := range tracer.Spans() {
+				spansByName[span.Name] = span
+			}
+
+			sendSpan := spansByName["sqs.SendJSON"]
+			handleSpan := spansByName["handle"]
+
+			assert.Equal(t, "bar", aws.StringValue(message.MessageAttributes[sqs.MessageTypeAttribute].StringValue))
+			assert.Equal(t, "bar", sendSpan.Attributes["message_type"])
+			assert.True(t, sendSpan.Ended)
+			assert.True(t, spansByName["sqs.EnsureQueue"].Ended)
+			assert.Equal(t, sendSpan.TraceID, handleSpan.TraceID)
+			assert.Equal(t, sendSpan.ID, handleSpan.ParentID)
+		})
+	})
+
+	t.Run(".PeekMessages()", func(t *testing.T) {
+		t.Run("StartsSpanForQueue", func(t *testing.T) {
+			tracer := awswrappers.NewInMemoryTracer()
+
+			mock := MockSDKClient{
+				mockReceiveMessage: onceReceiveMessage(deadLetterMessages("1", "2")...),
+				mockChangeMessageVisibility: func(input *sqsLib.ChangeMessageVisibilityInput) (*sqsLib.ChangeMessageVisibilityOutput, error) {
+					return &sqsLib.ChangeMessageVisibilityOutput{}, nil
+				},
+			}
+
+			client := sqs.NewClientWithOptions(
+				sqs.WithAPI(&mock),
+				sqs.WithClientConfig(&sqs.ClientConfig{QueueEndpoint: "http://www.test.com"}),
+				sqs.WithDevelopmentMode(true),
+				sqs.WithTracer(tracer),
+			)
+
+			_, err := client.PeekMessages("foo-dlq", 5)
+			assert.NoError(t, err)
+
+			spans := tracer.Spans()
+			peekSpan := spans[0]
+
+			assert.Equal(t, "sqs.PeekMessages", peekSpan.Name)
+			assert.Equal(t, "foo-dlq", peekSpan.Attributes["queue"])
+			assert.Equal(t, 2, peekSpan.Attributes["messages"])
+			assert.True(t, peekSpan.Ended)
+
+			for _, span := range spans[1:] {
+				assert.Equal(t, peekSpan.ID, span.ParentID)
+			}
+		})
+	})
+}
@@ -0,0 +1,195 @@
+package awswrappers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	// TraceParentKey is the carrier key the InMemoryTracer propagates trace
+	// context with, it follows the W3C traceparent format.
+	TraceParentKey = "traceparent"
+)
+
+type (
+	// Tracer starts spans around the wrapper calls and propagates trace
+	// context through message attributes, it is modelled on OpenTelemetry so
+	// an adapter can be written for any tracing library.
+	Tracer interface {
+		// StartSpan starts a span as a child of any span in the context,
+		// returning a context holding the new span.
+		StartSpan(ctx context.Context, name string) (context.Context, Span)
+
+		// Inject adds the trace context of the span in the context to the
+		// carrier.
+		Inject(ctx context.Context, carrier map[string]string)
+
+		// Extract returns a context holding the trace context in the
+		// carrier, or the given context when the carrier has none.
+		Extract(ctx context.Context, carrier map[string]string) context.Context
+	}
+
+	// Span is a single traced operation.
+	Span interface {
+		SetAttribute(key string, value interface{})
+		RecordError(err error)
+		End()
+	}
+
+	// NopTracer starts spans that record nothing and propagates no trace
+	// context.
+	NopTracer struct{}
+
+	// InMemoryTracer keeps every span it starts, it is intended for tests.
+	InMemoryTracer struct {
+		mutex  sync.Mutex
+		nextID uint64
+		spans  []*InMemorySpan
+	}
+
+	// InMemorySpan is a span started by an InMemoryTracer.
+	InMemorySpan struct {
+		Attributes Fields
+		Ended      bool
+		Err        error
+		ID         string
+		Name       string
+		ParentID   string
+		TraceID    string
+		tracer     *InMemoryTracer
+	}
+
+	nopSpan struct{}
+
+	spanContext struct {
+		spanID  string
+		traceID string
+	}
+
+	spanContextKey struct{}
+)
+
+// EndSpan records the error, if any, and ends the span.
+func EndSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	span.End()
+}
+
+// StartSpan returns the context unchanged and a span that records nothing.
+func (NopTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+// Inject adds nothing to the carrier.
+func (NopTracer) Inject(ctx context.Context, carrier map[string]string) {}
+
+// Extract returns the context unchanged.
+func (NopTracer) Extract(ctx context.Context, carrier map[string]string) context.Context {
+	return ctx
+}
+
+func (nopSpan) SetAttribute(key string, value interface{}) {}
+
+func (nopSpan) RecordError(err error) {}
+
+func (nopSpan) End() {}
+
+// NewInMemoryTracer creates a new InMemoryTracer with no spans.
+func NewInMemoryTracer() *InMemoryTracer {
+	return &InMemoryTracer{}
+}
+
+// StartSpan starts a span as a child of the span or extracted trace
+// context in the context, or a span in a new trace if there is none.
+func (t *InMemoryTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.nextID++
+
+	span := &InMemorySpan{
+		Attributes: Fields{},
+		ID:         fmt.Sprintf("%016x", t.nextID),
+		Name:       name,
+		TraceID:    fmt.Sprintf("%032x", t.nextID),
+		tracer:     t,
+	}
+
+	if parent, ok := ctx.Value(spanContextKey{}).(spanContext); ok {
+		span.ParentID = parent.spanID
+		span.TraceID = parent.traceID
+	}
+
+	t.spans = append(t.spans, span)
+
+	return context.WithValue(ctx, spanContextKey{}, spanContext{span.ID, span.TraceID}), span
+}
+
+// Inject adds the trace context in the context to the carrier as a
+// TraceParentKey.
+func (t *InMemoryTracer) Inject(ctx context.Context, carrier map[string]string) {
+	if current, ok := ctx.Value(spanContextKey{}).(spanContext); ok {
+		carrier[TraceParentKey] = fmt.Sprintf("00-%s-%s-01", current.traceID, current.spanID)
+	}
+}
+
+// Extract returns a context holding the trace context of the carrier's
+// TraceParentKey.
+func (t *InMemoryTracer) Extract(ctx context.Context, carrier map[string]string) context.Context {
+	parts := strings.Split(carrier[TraceParentKey], "-")
+	if len(parts) != 4 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, spanContextKey{}, spanContext{parts[2], parts[1]})
+}
+
+// Spans returns a copy of the spans started so far in the order they were
+// started.
+func (t *InMemoryTracer) Spans() []InMemorySpan {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	spans := make([]InMemorySpan, 0, len(t.spans))
+	for _, span := range t.spans {
+		spanCopy := *span
+		spanCopy.Attributes = Fields{}
+
+		for key, value := range span.Attributes {
+			spanCopy.Attributes[key] = value
+		}
+
+		spans = append(spans, spanCopy)
+	}
+
+	return spans
+}
+
+// SetAttribute sets an attribute of the span.
+func (s *InMemorySpan) SetAttribute(key string, value interface{}) {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+
+	s.Attributes[key] = value
+}
+
+// RecordError sets the error of the span.
+func (s *InMemorySpan) RecordError(err error) {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+
+	s.Err = err
+}
+
+// End marks the span as ended.
+func (s *InMemorySpan) End() {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+
+	s.Ended = true
+}
@@ -0,0 +1,71 @@
+package awswrappers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vidsy/awswrappers"
+)
+
+func TestTracing(t *testing.T) {
+	t.Run("InMemoryTracer", func(t *testing.T) {
+		t.Run("StartsChildSpans", func(t *testing.T) {
+			tracer := awswrappers.NewInMemoryTracer()
+
+			ctx, parent := tracer.StartSpan(context.Background(), "parent")
+			_, child := tracer.StartSpan(ctx, "child")
+			child.SetAttribute("queue", "foo")
+			awswrappers.EndSpan(child, errors.New("Child error"))
+
+			spans := tracer.Spans()
+			if assert.Len(t, spans, 2) {
+				assert.Equal(t, spans[0].TraceID, spans[1].TraceID)
+				assert.Equal(t, spans[0].ID, spans[1].ParentID)
+				assert.Equal(t, "foo", spans[1].Attributes["queue"])
+				assert.EqualError(t, spans[1].Err, "Child error")
+				assert.True(t, spans[1].Ended)
+				assert.False(t, spans[0].Ended)
+			}
+
+			parent.End()
+		})
+
+		t.Run("PropagatesThroughCarrier", func(t *testing.T) {
+			tracer := awswrappers.NewInMemoryTracer()
+
+			ctx, _ := tracer.StartSpan(context.Background(), "send")
+			carrier := map[string]string{}
+			tracer.Inject(ctx, carrier)
+
+			_, span := tracer.StartSpan(tracer.Extract(context.Background(), carrier), "receive")
+			span.End()
+
+			spans := tracer.Spans()
+			assert.Contains(t, carrier, awswrappers.TraceParentKey)
+			assert.Equal(t, spans[0].TraceID, spans[1].TraceID)
+			assert.Equal(t, spans[0].ID, spans[1].ParentID)
+		})
+
+		t.Run("IgnoresMissingTraceContext", func(t *testing.T) {
+			tracer := awswrappers.NewInMemoryTracer()
+			ctx := context.Background()
+
+			assert.Equal(t, ctx, tracer.Extract(ctx, map[string]string{}))
+		})
+	})
+
+	t.Run("NopTracer", func(t *testing.T) {
+		t.Run("PropagatesNothing", func(t *testing.T) {
+			tracer := awswrappers.NopTracer{}
+			ctx, span := tracer.StartSpan(context.Background(), "span")
+			span.End()
+
+			carrier := map[string]string{}
+			tracer.Inject(ctx, carrier)
+
+			assert.Empty(t, carrier)
+		})
+	})
+}